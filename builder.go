@@ -0,0 +1,287 @@
+package cronrange
+
+import (
+	"fmt"
+	"time"
+)
+
+// RuleBuilder makes rules programmatically, without writing and parsing expressions, e.g.
+//
+//	rule, err := cronrange.NewRule().Between(9, 0, 17, 0).Weekdays(1, 2, 3, 4, 5).Build()
+//
+// Methods can be chained, the first invalid argument is reported by Build.
+type RuleBuilder struct {
+	rule Rule
+	err  error
+}
+
+// NewRule makes a builder of a rule active all day on every day, the same as "* * * *"
+func NewRule() *RuleBuilder {
+	return &RuleBuilder{rule: Rule{
+		timeRange: TimeRange{all: true},
+		dow:       Field{all: true, names: dowNames, min: 0, max: 6},
+		dom:       Field{all: true, min: 1, max: 31},
+		month:     Field{all: true, names: monthNames, min: 1, max: 12},
+	}}
+}
+
+// Edit makes a builder starting from the rule, to change some of its parts and keep the rest
+func (r Rule) Edit() *RuleBuilder {
+	return &RuleBuilder{rule: r}
+}
+
+// NewTimeRange makes a time range from start to end, both given as duration since midnight with
+// one second precision. The end is inclusive, and a range ending before it starts spans across midnight.
+func NewTimeRange(start, end time.Duration) (TimeRange, error) {
+	for _, d := range []time.Duration{start, end} {
+		if d < 0 || d >= 24*time.Hour {
+			return TimeRange{}, fmt.Errorf("time %s out of range", d)
+		}
+		if d%time.Second != 0 {
+			return TimeRange{}, fmt.Errorf("time %s is not a whole second", d)
+		}
+	}
+	return TimeRange{
+		start:      start,
+		end:        end,
+		overnight:  end < start,
+		hasSeconds: start%time.Minute != 0 || end%time.Minute != 0,
+	}, nil
+}
+
+// Between sets the time range from startHour:startMin to endHour:endMin, with the end included
+func (b *RuleBuilder) Between(startHour, startMin, endHour, endMin int) *RuleBuilder {
+	for _, v := range []int{startHour, endHour} {
+		if v < 0 || v > 23 {
+			return b.fail(fmt.Errorf("invalid hour %d", v))
+		}
+	}
+	for _, v := range []int{startMin, endMin} {
+		if v < 0 || v > 59 {
+			return b.fail(fmt.Errorf("invalid minute %d", v))
+		}
+	}
+	start := time.Duration(startHour)*time.Hour + time.Duration(startMin)*time.Minute
+	end := time.Duration(endHour)*time.Hour + time.Duration(endMin)*time.Minute
+	tr, err := NewTimeRange(start, end)
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.Range(tr)
+}
+
+// Range sets the time range
+func (b *RuleBuilder) Range(tr TimeRange) *RuleBuilder {
+	b.rule.timeRange = tr
+	return b
+}
+
+// AllDay makes the rule active all day, the same as * in the time field
+func (b *RuleBuilder) AllDay() *RuleBuilder {
+	b.rule.timeRange = TimeRange{all: true}
+	return b
+}
+
+// HalfOpen makes the end time of the range exclusive, e.g. 09:00-17:00) ends right before 17:00
+func (b *RuleBuilder) HalfOpen() *RuleBuilder {
+	b.rule.timeRange.halfOpen = true
+	return b
+}
+
+// Weekdays limits the rule to the days of week, all days if none given.
+// Day of week specifiers of the rule, like 5#3, are dropped, NthWeekday and LastOfMonth add them back.
+func (b *RuleBuilder) Weekdays(days ...time.Weekday) *RuleBuilder {
+	vals := make([]int, 0, len(days))
+	for _, d := range days {
+		vals = append(vals, int(d))
+	}
+	f, err := newField(vals, 0, 6, dowNames)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid dow: %w", err))
+	}
+	b.rule.dow = f
+	return b
+}
+
+// MonthDays limits the rule to the days of month, all days if none given.
+// Day of month specifiers of the rule, like L or H, are dropped, LastDay, NearestWeekday,
+// LastWeekday and Holidays add them back.
+func (b *RuleBuilder) MonthDays(days ...int) *RuleBuilder {
+	f, err := newField(days, 1, 31, nil)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid dom: %w", err))
+	}
+	b.rule.dom = f
+	return b
+}
+
+// LastDay adds the last day of month to the days of month, or the day offset days before it,
+// the same as L or L-n
+func (b *RuleBuilder) LastDay(offset int) *RuleBuilder {
+	if offset < 0 || offset > 30 {
+		return b.fail(fmt.Errorf("invalid dom: last day offset %d out of range 0-30", offset))
+	}
+	addSpec(&b.rule.dom, daySpec{kind: lastDay, value: offset})
+	return b
+}
+
+// NearestWeekday adds the weekday nearest to the day within the same month to the days of month,
+// the same as nW
+func (b *RuleBuilder) NearestWeekday(day int) *RuleBuilder {
+	if day < 1 || day > 31 {
+		return b.fail(fmt.Errorf("invalid dom: nearest weekday day %d out of range 1-31", day))
+	}
+	addSpec(&b.rule.dom, daySpec{kind: nearestWeekday, value: day})
+	return b
+}
+
+// LastWeekday adds the last weekday (Mon-Fri) of month to the days of month, the same as LW
+func (b *RuleBuilder) LastWeekday() *RuleBuilder {
+	addSpec(&b.rule.dom, daySpec{kind: lastMonthWeekday})
+	return b
+}
+
+// Holidays adds the holidays of the rule's calendar to the days of month, the same as H.
+// Build fails if the rule has no calendar.
+func (b *RuleBuilder) Holidays() *RuleBuilder {
+	addSpec(&b.rule.dom, daySpec{kind: holiday})
+	return b
+}
+
+// NthWeekday adds the n-th day d of month to the days of week, the same as d#n
+func (b *RuleBuilder) NthWeekday(d time.Weekday, n int) *RuleBuilder {
+	if d < time.Sunday || d > time.Saturday {
+		return b.fail(fmt.Errorf("invalid dow: weekday %d out of range 0-6", d))
+	}
+	if n < 1 || n > 5 {
+		return b.fail(fmt.Errorf("invalid dow: weekday occurrence %d out of range 1-5", n))
+	}
+	addSpec(&b.rule.dow, daySpec{kind: nthWeekday, value: int(d), nth: n})
+	return b
+}
+
+// LastOfMonth adds the last day d of month to the days of week, the same as dL
+func (b *RuleBuilder) LastOfMonth(d time.Weekday) *RuleBuilder {
+	if d < time.Sunday || d > time.Saturday {
+		return b.fail(fmt.Errorf("invalid dow: weekday %d out of range 0-6", d))
+	}
+	addSpec(&b.rule.dow, daySpec{kind: lastWeekdayOfMonth, value: int(d)})
+	return b
+}
+
+// Months limits the rule to the months, all months if none given
+func (b *RuleBuilder) Months(months ...time.Month) *RuleBuilder {
+	vals := make([]int, 0, len(months))
+	for _, m := range months {
+		vals = append(vals, int(m))
+	}
+	f, err := newField(vals, 1, 12, monthNames)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid month: %w", err))
+	}
+	b.rule.month = f
+	return b
+}
+
+// Dates limits the rule to the date ranges, no limit if none given. Only calendar days of From and To
+// are used, as seen in their locations.
+func (b *RuleBuilder) Dates(ranges ...DateRange) *RuleBuilder {
+	var dates []dateRange
+	for _, dr := range ranges {
+		from, to := dayNumber(dr.From, dr.From.Location()), dayNumber(dr.To, dr.To.Location())
+		if to < from {
+			return b.fail(fmt.Errorf("invalid dates: date range %s..%s ends before it starts",
+				dr.From.Format(dateLayout), dr.To.Format(dateLayout)))
+		}
+		dates = append(dates, dateRange{from: from, to: to})
+	}
+	sortDates(dates)
+	b.rule.dates = dates
+	return b
+}
+
+// In sets the timezone the rule is evaluated in, nil for the location of the checked time
+func (b *RuleBuilder) In(loc *time.Location) *RuleBuilder {
+	b.rule.loc = loc
+	return b
+}
+
+// DST sets the DST policy of the rule
+func (b *RuleBuilder) DST(p DSTPolicy) *RuleBuilder {
+	if p != DSTWall && p != DSTElapsed {
+		return b.fail(fmt.Errorf("invalid DST policy %d", p))
+	}
+	b.rule.dst = p
+	return b
+}
+
+// DaysOr sets whether dom and dow are ORed when both are restricted, like in classic cron
+func (b *RuleBuilder) DaysOr(or bool) *RuleBuilder {
+	b.rule.daysOr = or
+	return b
+}
+
+// Exclusion sets whether the rule is an exclusion rule, i.e. the time must not match it
+func (b *RuleBuilder) Exclusion(exclude bool) *RuleBuilder {
+	b.rule.exclude = exclude
+	return b
+}
+
+// Calendar sets the holiday calendar of the rule by its registered name, an empty name removes it
+func (b *RuleBuilder) Calendar(name string) *RuleBuilder {
+	if name == "" {
+		b.rule.cal, b.rule.calName = nil, ""
+		return b
+	}
+	cal, ok := lookupCalendar(name)
+	if !ok {
+		return b.fail(fmt.Errorf("unknown calendar %q", name))
+	}
+	b.rule.cal, b.rule.calName = cal, name
+	return b
+}
+
+// Build returns the rule or the first error of the builder methods
+func (b *RuleBuilder) Build() (Rule, error) {
+	if b.err != nil {
+		return Rule{}, b.err
+	}
+	if b.rule.timeRange.all && b.rule.timeRange.halfOpen {
+		return Rule{}, fmt.Errorf("all day time range can't be half-open")
+	}
+	if b.rule.cal == nil && containsSpec(b.rule.dom.specs, daySpec{kind: holiday}) {
+		return Rule{}, fmt.Errorf("invalid dom: H requires a calendar")
+	}
+	return b.rule, nil
+}
+
+// fail records the first error of the builder
+func (b *RuleBuilder) fail(err error) *RuleBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// addSpec adds the day specifier to the field, a field matching all values is limited to the specifier.
+// The specifiers are copied, as the field may be shared with the rule being edited.
+func addSpec(f *Field, spec daySpec) {
+	if f.all {
+		f.all, f.values, f.specs = false, 0, nil
+	}
+	if !containsSpec(f.specs, spec) {
+		f.specs = append(append([]daySpec(nil), f.specs...), spec)
+	}
+}
+
+// newField makes a field with the given values, or matching all values if there are none
+func newField(vals []int, min, max int, names []string) (Field, error) {
+	f := Field{all: len(vals) == 0, names: names, min: min, max: max}
+	for _, v := range vals {
+		if v < min || v > max {
+			return Field{}, fmt.Errorf("value %d out of range %d-%d", v, min, max)
+		}
+		f.values |= 1 << v
+	}
+	return f, nil
+}
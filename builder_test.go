@@ -0,0 +1,250 @@
+package cronrange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRuleBuilder(t *testing.T) {
+	RegisterCalendar("test-builder", NewHolidays())
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		builder *RuleBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "default",
+			builder: NewRule(),
+			want:    "* * * *",
+		},
+		{
+			name:    "weekdays business hours",
+			builder: NewRule().Between(9, 0, 17, 0).Weekdays(1, 2, 3, 4, 5),
+			want:    "09:00-17:00 1-5 * *",
+		},
+		{
+			name:    "weekday and month constants",
+			builder: NewRule().Weekdays(time.Saturday, time.Sunday).Months(time.June, time.July, time.August),
+			want:    "* 0,6 * 6-8",
+		},
+		{
+			name:    "overnight half-open",
+			builder: NewRule().Between(22, 30, 2, 0).HalfOpen().MonthDays(1, 15),
+			want:    "22:30-02:00) * 1,15 *",
+		},
+		{
+			name: "range with seconds",
+			builder: NewRule().Range(TimeRange{}).Range(mustTimeRange(t, 9*time.Hour+30*time.Second,
+				10*time.Hour)),
+			want: "09:00:30-10:00:00 * * *",
+		},
+		{
+			name: "options and dates",
+			builder: NewRule().In(berlin).DST(DSTElapsed).DaysOr(true).Calendar("test-builder").
+				Weekdays(1).MonthDays(13).Exclusion(true).
+				Dates(DateRange{From: day(2027, 1, 1), To: day(2027, 12, 31)},
+					DateRange{From: day(2026, 12, 24), To: day(2026, 12, 24)}),
+			want: "!TZ=Europe/Berlin DST=elapsed DAYS=or CAL=test-builder * 1 13 * 2026-12-24,2027",
+		},
+		{
+			name:    "all day again",
+			builder: NewRule().Between(9, 0, 17, 0).AllDay(),
+			want:    "* * * *",
+		},
+		{
+			name:    "day of month specifiers",
+			builder: NewRule().MonthDays(1, 15).LastDay(0).LastDay(2).NearestWeekday(10).LastWeekday().LastDay(0),
+			want:    "* * 1,15,10W,L,L-2,LW *",
+		},
+		{
+			name:    "day of week specifiers",
+			builder: NewRule().Between(9, 0, 12, 0).NthWeekday(time.Friday, 3).LastOfMonth(time.Monday),
+			want:    "09:00-12:00 1L,5#3 * *",
+		},
+		{
+			name:    "holidays",
+			builder: NewRule().Calendar("test-builder").Weekdays(0, 6).Holidays().DaysOr(true),
+			want:    "DAYS=or CAL=test-builder * 0,6 H *",
+		},
+		{
+			name:    "holidays without calendar",
+			builder: NewRule().Holidays(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid last day offset",
+			builder: NewRule().LastDay(31),
+			wantErr: true,
+		},
+		{
+			name:    "invalid nearest weekday",
+			builder: NewRule().NearestWeekday(0),
+			wantErr: true,
+		},
+		{
+			name:    "invalid weekday occurrence",
+			builder: NewRule().NthWeekday(time.Friday, 6),
+			wantErr: true,
+		},
+		{
+			name:    "invalid last weekday of month",
+			builder: NewRule().LastOfMonth(7),
+			wantErr: true,
+		},
+		{
+			name:    "invalid hour",
+			builder: NewRule().Between(9, 0, 24, 0),
+			wantErr: true,
+		},
+		{
+			name:    "invalid minute",
+			builder: NewRule().Between(9, 60, 17, 0),
+			wantErr: true,
+		},
+		{
+			name:    "invalid weekday",
+			builder: NewRule().Weekdays(7),
+			wantErr: true,
+		},
+		{
+			name:    "invalid day of month",
+			builder: NewRule().MonthDays(0),
+			wantErr: true,
+		},
+		{
+			name:    "invalid month",
+			builder: NewRule().Months(13),
+			wantErr: true,
+		},
+		{
+			name:    "first error is kept",
+			builder: NewRule().Months(13).Between(9, 0, 17, 0),
+			wantErr: true,
+		},
+		{
+			name:    "reversed dates",
+			builder: NewRule().Dates(DateRange{From: day(2026, 2, 1), To: day(2026, 1, 1)}),
+			wantErr: true,
+		},
+		{
+			name:    "unknown calendar",
+			builder: NewRule().Calendar("test-unknown"),
+			wantErr: true,
+		},
+		{
+			name:    "half-open all day",
+			builder: NewRule().HalfOpen(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("Build() = %q, want %q", got, tt.want)
+			}
+
+			// the built rule must behave the same as the parsed one
+			parsed, err := Parse(tt.want)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.want, err)
+			}
+			for tm := day(2026, 12, 20); tm.Before(day(2027, 1, 20)); tm = tm.Add(37 * time.Minute) {
+				if got, want := Match([]Rule{rule}, tm), Match(parsed, tm); got != want {
+					t.Fatalf("Match() at %v = %v, parsed rule = %v", tm, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRuleEdit(t *testing.T) {
+	rules, err := Parse("TZ=UTC 09:00-17:00 1-5 L * 2026")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edited, err := rules[0].Edit().Between(8, 0, 12, 0).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := edited.String(), "TZ=UTC 08:00-12:00 1-5 L * 2026"; got != want {
+		t.Errorf("Edit() = %q, want %q", got, want)
+	}
+	if got, want := rules[0].String(), "TZ=UTC 09:00-17:00 1-5 L * 2026"; got != want {
+		t.Errorf("original rule changed to %q, want %q", got, want)
+	}
+
+	edited, err = rules[0].Edit().LastDay(1).NthWeekday(time.Monday, 1).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := edited.String(), "TZ=UTC 09:00-17:00 1-5,1#1 L,L-1 * 2026"; got != want {
+		t.Errorf("Edit() = %q, want %q", got, want)
+	}
+	if got, want := rules[0].String(), "TZ=UTC 09:00-17:00 1-5 L * 2026"; got != want {
+		t.Errorf("original rule changed to %q, want %q", got, want)
+	}
+
+	edited, err = rules[0].Edit().MonthDays().Weekdays().Dates().In(nil).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := edited.String(), "09:00-17:00 * * *"; got != want {
+		t.Errorf("Edit() = %q, want %q", got, want)
+	}
+}
+
+func TestNewTimeRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end time.Duration
+		want       string
+		wantErr    bool
+	}{
+		{name: "minutes", start: 9 * time.Hour, end: 17*time.Hour + 30*time.Minute, want: "09:00-17:30"},
+		{name: "seconds", start: 9*time.Hour + 15*time.Second, end: 17 * time.Hour, want: "09:00:15-17:00:00"},
+		{name: "overnight", start: 22 * time.Hour, end: 2 * time.Hour, want: "22:00-02:00"},
+		{name: "negative", start: -time.Hour, end: 2 * time.Hour, wantErr: true},
+		{name: "full day", start: 0, end: 24 * time.Hour, wantErr: true},
+		{name: "fraction of second", start: time.Millisecond, end: time.Hour, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := NewTimeRange(tt.start, tt.end)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTimeRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := tr.String(); got != tt.want {
+				t.Errorf("NewTimeRange() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustTimeRange(t *testing.T, start, end time.Duration) TimeRange {
+	t.Helper()
+	tr, err := NewTimeRange(start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
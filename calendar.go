@@ -0,0 +1,215 @@
+package cronrange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Calendar defines holidays referenced by rules with the H day of month specifier
+type Calendar interface {
+	// IsHoliday checks if the calendar day of t is a holiday. Rules call it with midnight UTC
+	// of the checked day, so implementations should rely on t.Date() only.
+	IsHoliday(t time.Time) bool
+}
+
+// CalendarFunc is an adapter to use an ordinary function as a Calendar
+type CalendarFunc func(t time.Time) bool
+
+// IsHoliday calls f(t)
+func (f CalendarFunc) IsHoliday(t time.Time) bool {
+	return f(t)
+}
+
+// calendars is a registry of named calendars, referenced by rules with the CAL option
+var calendars = struct {
+	sync.RWMutex
+	m map[string]Calendar
+}{m: map[string]Calendar{}}
+
+// RegisterCalendar makes the calendar available to rules by name, e.g. "CAL=uk * * H *" matches
+// holidays of the calendar registered as "uk". Rules resolve calendars when parsed, so the calendar
+// must be registered before parsing. Registering a calendar with the same name replaces the previous one
+// for rules parsed after that.
+func RegisterCalendar(name string, cal Calendar) {
+	calendars.Lock()
+	defer calendars.Unlock()
+	calendars.m[name] = cal
+}
+
+// lookupCalendar returns the registered calendar by name
+func lookupCalendar(name string) (Calendar, bool) {
+	calendars.RLock()
+	defer calendars.RUnlock()
+	cal, ok := calendars.m[name]
+	return cal, ok
+}
+
+// Holidays is a Calendar with a fixed set of dates
+type Holidays struct {
+	days map[time.Time]bool // midnight UTC of every holiday
+}
+
+// NewHolidays makes a Holidays calendar with the calendar days of the given times
+func NewHolidays(dates ...time.Time) *Holidays {
+	h := &Holidays{days: make(map[time.Time]bool, len(dates))}
+	for _, d := range dates {
+		h.Add(d)
+	}
+	return h
+}
+
+// Add adds the calendar day of t to holidays
+func (h *Holidays) Add(t time.Time) {
+	h.days[dateOf(t)] = true
+}
+
+// IsHoliday checks if the calendar day of t is one of the holidays
+func (h *Holidays) IsHoliday(t time.Time) bool {
+	return h.days[dateOf(t)]
+}
+
+// Len returns the number of holidays
+func (h *Holidays) Len() int {
+	return len(h.days)
+}
+
+// dateOf returns the calendar day of t as midnight UTC
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// LoadDates reads holidays from a simple list of dates in YYYY-MM-DD format, one per line.
+// Besides plain lists it accepts CSV with the date in the first column and an optional header line,
+// and YAML lists of dates ("- 2026-12-25"), optionally under a single top-level key.
+// Empty lines and comments starting with # are ignored. The first line is taken as a CSV header only
+// if its first column has no digits, any other invalid date is an error, as is input with lines
+// but without a single date.
+func LoadDates(rdr io.Reader) (*Holidays, error) {
+	h := NewHolidays()
+	scanner := bufio.NewScanner(rdr)
+	lineNum, seenData := 0, false
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" || strings.HasSuffix(line, ":") || line == "---" {
+			continue // empty line, comment, YAML key or document marker
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "- "))
+		field, _, _ := strings.Cut(line, ",")
+		field = strings.Trim(strings.TrimSpace(field), `"'`)
+		day, err := time.Parse(dateLayout, field)
+		if err != nil {
+			if !seenData && !strings.ContainsAny(field, "0123456789") {
+				seenData = true // CSV header, like "date,name"
+				continue
+			}
+			return nil, fmt.Errorf("invalid date %q at line %d, want YYYY-MM-DD", field, lineNum)
+		}
+		seenData = true
+		h.Add(day)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading dates: %w", err)
+	}
+	if seenData && h.Len() == 0 {
+		return nil, fmt.Errorf("no dates found in %d lines", lineNum)
+	}
+	return h, nil
+}
+
+// LoadICS reads holidays from an iCalendar (.ics) stream. Every day covered by a VEVENT is a holiday,
+// all-day events span from DTSTART to the day before DTEND, events with time cover the day of DTSTART.
+// Recurrence rules are not expanded.
+func LoadICS(rdr io.Reader) (*Holidays, error) {
+	h := NewHolidays()
+
+	var lines []string
+	scanner := bufio.NewScanner(rdr)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:] // folded line continues the previous one
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading calendar: %w", err)
+	}
+
+	var inEvent bool
+	var start, end time.Time
+	var allDay bool
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		prop, _, _ := strings.Cut(name, ";") // parameters like VALUE=DATE or TZID are not needed
+		switch strings.ToUpper(prop) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent, start, end, allDay = true, time.Time{}, time.Time{}, false
+			}
+		case "DTSTART", "DTEND":
+			if !inEvent {
+				continue
+			}
+			day, isDate, err := parseICSDate(value)
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(prop, "DTSTART") {
+				start, allDay = day, isDate
+				continue
+			}
+			end = day
+		case "END":
+			if !strings.EqualFold(value, "VEVENT") || !inEvent {
+				continue
+			}
+			inEvent = false
+			if start.IsZero() {
+				return nil, fmt.Errorf("event without DTSTART")
+			}
+			h.Add(start)
+			if allDay {
+				for d := start.AddDate(0, 0, 1); d.Before(end); d = d.AddDate(0, 0, 1) {
+					h.Add(d)
+				}
+			}
+		}
+	}
+	return h, nil
+}
+
+// parseICSDate parses the value of DTSTART or DTEND and returns its calendar day as midnight UTC,
+// as written in the value. It reports whether the value is a date without time.
+func parseICSDate(value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if len(value) == len("20060102") {
+		day, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return day, true, nil
+	}
+
+	if len(value) < len("20060102T150405") || value[8] != 'T' {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+	}
+	day, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+	}
+	return day, false, nil
+}
@@ -0,0 +1,242 @@
+package cronrange
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadDates(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "plain list with comments",
+			input: "# bank holidays\n2026-12-25\n\n2026-12-26 # boxing day\n",
+			want:  []string{"2026-12-25", "2026-12-26"},
+		},
+		{
+			name:  "csv with header",
+			input: "date,name\n2026-12-25,Christmas Day\n2026-12-26,Boxing Day\n",
+			want:  []string{"2026-12-25", "2026-12-26"},
+		},
+		{
+			name:  "yaml list",
+			input: "---\nholidays:\n  - 2026-12-25\n  - \"2026-12-26\"\n",
+			want:  []string{"2026-12-25", "2026-12-26"},
+		},
+		{
+			name:  "empty",
+			input: "",
+		},
+		{
+			name:    "invalid date",
+			input:   "2026-12-25\n2026-13-01\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid first date",
+			input:   "2026-02-30\n2026-12-25\n",
+			wantErr: true,
+		},
+		{
+			name:    "wrong date format",
+			input:   "2026/12/25\n",
+			wantErr: true,
+		},
+		{
+			name:    "csv header only",
+			input:   "date,name\n",
+			wantErr: true,
+		},
+		{
+			name:  "comments only",
+			input: "# no holidays this year\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadDates(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadDates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			checkHolidays(t, got, tt.want)
+		})
+	}
+}
+
+func TestLoadICS(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "all-day events",
+			input: "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+				"BEGIN:VEVENT\r\nSUMMARY:Christmas Day\r\nDTSTART;VALUE=DATE:20261225\r\nDTEND;VALUE=DATE:20261226\r\nEND:VEVENT\r\n" +
+				"BEGIN:VEVENT\r\nSUMMARY:Easter\r\nDTSTART;VALUE=DATE:20260403\r\nDTEND;VALUE=DATE:20260407\r\nEND:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+			want: []string{"2026-04-03", "2026-04-04", "2026-04-05", "2026-04-06", "2026-12-25"},
+		},
+		{
+			name: "event without end and event with time",
+			input: "BEGIN:VCALENDAR\n" +
+				"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20260101\nEND:VEVENT\n" +
+				"BEGIN:VEVENT\nDTSTART:20260501T090000Z\nDTEND:20260503T170000Z\nEND:VEVENT\n" +
+				"END:VCALENDAR\n",
+			want: []string{"2026-01-01", "2026-05-01"},
+		},
+		{
+			name: "folded lines",
+			input: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:A very long\n  summary\nDTSTART;VALUE=\n DATE:20261231\n" +
+				"END:VEVENT\nEND:VCALENDAR\n",
+			want: []string{"2026-12-31"},
+		},
+		{
+			name:    "invalid date",
+			input:   "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20261332\nEND:VEVENT\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid date-time",
+			input:   "BEGIN:VEVENT\nDTSTART:2026-12-31 10:00\nEND:VEVENT\n",
+			wantErr: true,
+		},
+		{
+			name:    "event without start",
+			input:   "BEGIN:VEVENT\nSUMMARY:nothing\nEND:VEVENT\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadICS(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadICS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			checkHolidays(t, got, tt.want)
+		})
+	}
+}
+
+func checkHolidays(t *testing.T, h *Holidays, want []string) {
+	t.Helper()
+	if h.Len() != len(want) {
+		t.Errorf("got %d holidays, want %d", h.Len(), len(want))
+	}
+	for _, d := range want {
+		day, err := time.Parse(dateLayout, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !h.IsHoliday(day) {
+			t.Errorf("%s is not a holiday", d)
+		}
+	}
+}
+
+func TestHolidays(t *testing.T) {
+	h := NewHolidays(time.Date(2026, 12, 25, 15, 0, 0, 0, time.UTC))
+	h.Add(time.Date(2026, 12, 26, 0, 0, 0, 0, time.FixedZone("X", 3600)))
+
+	if !h.IsHoliday(time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Dec 25 is not a holiday")
+	}
+	if !h.IsHoliday(time.Date(2026, 12, 26, 23, 0, 0, 0, time.UTC)) {
+		t.Error("Dec 26 is not a holiday")
+	}
+	if h.IsHoliday(time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Dec 24 is a holiday")
+	}
+}
+
+func TestCalendarRules(t *testing.T) {
+	RegisterCalendar("test-bank", NewHolidays(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	RegisterCalendar("test-fridays", CalendarFunc(func(t time.Time) bool { return t.Weekday() == time.Friday }))
+
+	tests := []struct {
+		name    string
+		expr    string
+		time    time.Time
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "weekday except bank holiday",
+			expr: "09:00-17:00 1-5 * *; !CAL=test-bank * * H *",
+			time: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), // Monday, New Year
+			want: false,
+		},
+		{
+			name: "weekday which is not a holiday",
+			expr: "09:00-17:00 1-5 * *; !CAL=test-bank * * H *",
+			time: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name: "holiday combined with other days",
+			expr: "CAL=test-bank * * H,15 *",
+			time: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name: "calendar function",
+			expr: "CAL=test-fridays * * h *",
+			time: time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name:    "holiday without calendar",
+			expr:    "* * H *",
+			wantErr: true,
+		},
+		{
+			name:    "unknown calendar",
+			expr:    "CAL=test-unknown * * H *",
+			wantErr: true,
+		},
+		{
+			name:    "holiday in dow",
+			expr:    "CAL=test-bank * H * *",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := Parse(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := Match(rules, tt.time); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("string", func(t *testing.T) {
+		rules, err := Parse("!TZ=UTC CAL=test-bank * * 1,H *")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := rules[0].String(), "!TZ=UTC CAL=test-bank * * 1,H *"; got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	})
+}
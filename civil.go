@@ -0,0 +1,80 @@
+package cronrange
+
+import "time"
+
+// civil is a calendar day. Rules check days as civil values computed from the day number with integer
+// arithmetic, so matching doesn't need to call into time.Time accessors for every field.
+type civil struct {
+	days    int64 // days since 1970-01-01
+	year    int
+	month   time.Month
+	day     int
+	weekday time.Weekday
+}
+
+// civilFromDays returns the calendar day with the given number of days since 1970-01-01
+func civilFromDays(days int64) civil {
+	// shift the epoch to 0000-03-01, so leap days are at the end of the 400-year era and of each year
+	z := days + 719468
+	era := z / 146097
+	if z < 0 && z%146097 != 0 {
+		era--
+	}
+	doe := z - era*146097                                  // day of era, 0-146096
+	yoe := (doe - doe/1460 + doe/36524 - doe/146096) / 365 // year of era, 0-399
+	doy := doe - (365*yoe + yoe/4 - yoe/100)               // day of year starting March 1st, 0-365
+	mp := (5*doy + 2) / 153                                // month starting March, 0-11
+
+	c := civil{days: days, year: int(yoe + era*400), day: int(doy - (153*mp+2)/5 + 1)}
+	if mp < 10 {
+		c.month = time.Month(mp + 3)
+	} else {
+		c.month = time.Month(mp - 9)
+		c.year++
+	}
+
+	// 1970-01-01 is Thursday
+	wd := (days + 4) % 7
+	if wd < 0 {
+		wd += 7
+	}
+	c.weekday = time.Weekday(wd)
+	return c
+}
+
+// time returns the calendar day as midnight UTC
+func (c civil) time() time.Time {
+	return time.Unix(c.days*secondsPerDay, 0).UTC()
+}
+
+// wallClock returns the calendar day of t in its location as the number of days since 1970-01-01
+// and the wall-clock time of t as seconds since midnight
+func wallClock(t time.Time) (days, secs int64) {
+	_, offset := t.Zone()
+	wall := t.Unix() + int64(offset)
+	days = wall / secondsPerDay
+	if wall%secondsPerDay < 0 {
+		days--
+	}
+	return days, wall - days*secondsPerDay
+}
+
+// dayNumber returns the calendar day of t in loc as the number of days since 1970-01-01
+func dayNumber(t time.Time, loc *time.Location) int64 {
+	days, _ := wallClock(t.In(loc))
+	return days
+}
+
+// daysIn returns the number of days in the month
+func daysIn(year int, month time.Month) int {
+	switch month {
+	case time.February:
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 29
+		}
+		return 28
+	case time.April, time.June, time.September, time.November:
+		return 30
+	}
+	return 31
+}
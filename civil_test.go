@@ -0,0 +1,66 @@
+package cronrange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCivilFromDays(t *testing.T) {
+	// every day around the epoch and a sample of days far in the past and future
+	var days []int64
+	for d := int64(-800); d <= 800; d++ {
+		days = append(days, d)
+	}
+	for d := int64(-1_000_000); d <= 1_000_000; d += 997 {
+		days = append(days, d)
+	}
+
+	for _, d := range days {
+		tm := time.Unix(d*secondsPerDay, 0).UTC()
+		c := civilFromDays(d)
+		if c.year != tm.Year() || c.month != tm.Month() || c.day != tm.Day() || c.weekday != tm.Weekday() {
+			t.Fatalf("civilFromDays(%d) = %d-%02d-%02d %s, want %s", d, c.year, c.month, c.day, c.weekday,
+				tm.Format("2006-01-02 Monday"))
+		}
+		if !c.time().Equal(tm) {
+			t.Fatalf("civilFromDays(%d).time() = %s, want %s", d, c.time(), tm)
+		}
+	}
+}
+
+func TestWallClock(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		t    time.Time
+		day  string
+		secs int64
+	}{
+		{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "2024-01-01", 0},
+		{time.Date(2024, 1, 1, 23, 59, 59, 999, time.UTC), "2024-01-01", 86399},
+		{time.Date(1969, 12, 31, 12, 30, 0, 0, time.UTC), "1969-12-31", 45000},
+		{time.Date(2024, 3, 10, 3, 30, 0, 0, ny), "2024-03-10", 12600},
+		{time.Date(2024, 3, 10, 3, 30, 0, 0, ny).UTC(), "2024-03-10", 27000},
+		{time.Date(2024, 11, 3, 22, 0, 0, 0, ny), "2024-11-03", 79200},
+	}
+	for _, tt := range tests {
+		days, secs := wallClock(tt.t)
+		if day := civilFromDays(days).time().Format(dateLayout); day != tt.day || secs != tt.secs {
+			t.Errorf("wallClock(%s) = %s %d, want %s %d", tt.t, day, secs, tt.day, tt.secs)
+		}
+	}
+}
+
+func TestDaysIn(t *testing.T) {
+	for year := 1899; year <= 2101; year++ {
+		for m := time.January; m <= time.December; m++ {
+			want := time.Date(year, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+			if got := daysIn(year, m); got != want {
+				t.Fatalf("daysIn(%d, %s) = %d, want %d", year, m, got, want)
+			}
+		}
+	}
+}
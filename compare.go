@@ -0,0 +1,96 @@
+package cronrange
+
+import (
+	"iter"
+	"slices"
+	"sync"
+	"time"
+)
+
+// compareLocation is the location rules without a timezone are compared in besides UTC. It has clock changes
+// and an offset of a fraction of an hour, so rules only equal in UTC, like "TZ=UTC 09:00-17:00 * * *"
+// and "09:00-17:00 * * *", or only without clock changes, differ in it.
+var compareLocation = sync.OnceValue(func() *time.Location {
+	loc, err := time.LoadLocation("America/St_Johns")
+	if err != nil {
+		return time.FixedZone("UTC-03:30", -(3*60+30)*60) // no timezone database, keep the offset at least
+	}
+	return loc
+})
+
+// Equal checks if rule sets a and b are active at the same times, regardless of how the rules are written,
+// e.g. "09:00-12:00 1-5 * *; 12:00-17:00 1-5 * *" and "09:00-17:00 mon-fri * *" are equal.
+// Rule sets are compared the same way as by Covers.
+func Equal(a, b []Rule) bool {
+	for _, loc := range compareLocations(a, b) {
+		from, to := compareHorizon(loc, a, b)
+		if !equalSpans(activeSpans(a, from, to), activeSpans(b, from, to)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Covers checks if rule set a is active whenever rule set b is, e.g. to detect that a change widens
+// a window. Rule sets are compared within the 400-year Gregorian cycle starting in 2000, after which
+// calendar fields repeat, extended to all absolute dates of the rules. Timezone changes and holidays
+// outside of this period are not compared. Rules without a timezone are evaluated in the location
+// of the checked time, so they are compared in UTC and in a location with clock changes and a half-hour
+// offset; a rule without a timezone is not equal to the same rule with TZ=UTC. As such rules follow
+// the wall clock, the result holds in other locations too, except for DST=elapsed rules on days
+// with clock changes and rule sets mixing rules with and without a timezone, which may differ
+// in some other locations only.
+func Covers(a, b []Rule) bool {
+	for _, loc := range compareLocations(a, b) {
+		from, to := compareHorizon(loc, a, b)
+		for range subtractSpans(activeSpans(b, from, to), activeSpans(a, from, to)) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareLocations returns locations to compare rule sets in, UTC, and compareLocation if any rule
+// has no timezone and so depends on the location
+func compareLocations(sets ...[]Rule) []*time.Location {
+	for _, rules := range sets {
+		if slices.ContainsFunc(rules, func(r Rule) bool { return r.loc == nil }) {
+			return []*time.Location{time.UTC, compareLocation()}
+		}
+	}
+	return []*time.Location{time.UTC}
+}
+
+// equalSpans checks if a and b yield the same spans
+func equalSpans(a, b iter.Seq[span]) bool {
+	nextA, stopA := iter.Pull(a)
+	defer stopA()
+	nextB, stopB := iter.Pull(b)
+	defer stopB()
+	for {
+		sa, okA := nextA()
+		sb, okB := nextB()
+		if okA != okB || sa != sb {
+			return false
+		}
+		if !okA {
+			return true
+		}
+	}
+}
+
+// compareHorizon returns the period rule sets are compared within, in the given location, the 400-year
+// Gregorian cycle starting in 2000 extended to all absolute dates of the rules, with a margin for timezone
+// offsets and windows spilling over midnight
+func compareHorizon(loc *time.Location, sets ...[]Rule) (from, to time.Time) {
+	first := dayNumber(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.UTC)
+	last := dayNumber(time.Date(2000+searchHorizonYears, 1, 1, 0, 0, 0, 0, time.UTC), time.UTC)
+	for _, rules := range sets {
+		for _, r := range rules {
+			for _, dr := range r.dates {
+				first, last = min(first, dr.from), max(last, dr.to+1)
+			}
+		}
+	}
+	return civilFromDays(first - 2).time().In(loc), civilFromDays(last + 2).time().In(loc)
+}
@@ -0,0 +1,91 @@
+package cronrange
+
+import (
+	"testing"
+)
+
+func TestEqualAndCovers(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		equal    bool
+		aCoversB bool
+		bCoversA bool
+	}{
+		{name: "same", a: "09:00-17:00 1-5 * *", b: "09:00-17:00 1-5 * *", equal: true, aCoversB: true, bCoversA: true},
+		{name: "names", a: "09:00-17:00 1-5 * *", b: "09:00-17:00 mon-fri * *", equal: true, aCoversB: true, bCoversA: true},
+		{
+			name:  "split time range",
+			a:     "09:00-12:00 1-5 * *; 12:00-17:00 1-5 * *",
+			b:     "09:00-17:00 1-5 * *",
+			equal: true, aCoversB: true, bCoversA: true,
+		},
+		{
+			name:  "split overnight range",
+			a:     "22:00-02:00 * * *",
+			b:     "22:00-23:59:59 * * *; 00:00-02:00 * * *",
+			equal: true, aCoversB: true, bCoversA: true,
+		},
+		{name: "exclusion", a: "* * * *; !* 0 * *", b: "* 1-6 * *", equal: true, aCoversB: true, bCoversA: true},
+		{name: "never active", a: "!* * * *", b: "* * 30 2", equal: true, aCoversB: true, bCoversA: true},
+		{name: "no leap day in 2100", a: "* * 29 2 2100", b: "* * 30 2", equal: true, aCoversB: true, bCoversA: true},
+		{name: "dates beyond the cycle", a: "* * * * 2500", b: "* * 30 2", aCoversB: true},
+		{name: "dates before the cycle", a: "* * 1 1 1990", b: "* * 1 1 1991"},
+		// rules without a timezone are evaluated in the location of the checked time
+		{name: "utc", a: "TZ=UTC 09:00-17:00 * * *", b: "09:00-17:00 * * *"},
+		{
+			name:  "utc only",
+			a:     "TZ=UTC 09:00-17:00 * * *",
+			b:     "TZ=Etc/UTC 09:00-17:00 * * *",
+			equal: true, aCoversB: true, bCoversA: true,
+		},
+		{name: "dst policy without timezone", a: "DST=elapsed 01:00-04:00 * * *", b: "01:00-04:00 * * *"},
+		{name: "overnight after all-day", a: "* 1 * *; 22:00-02:00 1 * *", b: "* 1 * *", aCoversB: true},
+		{
+			name:  "dst policy without effect",
+			a:     "TZ=America/New_York 09:00-17:00 * * *",
+			b:     "TZ=America/New_York DST=elapsed 09:00-17:00 * * *",
+			equal: true, aCoversB: true, bCoversA: true,
+		},
+		{
+			name: "dst policy with effect",
+			a:    "TZ=America/New_York 01:00-04:00 * * *",
+			b:    "TZ=America/New_York DST=elapsed 01:00-04:00 * * *",
+		},
+		{name: "wider time", a: "08:00-18:00 1-5 * *", b: "09:00-17:00 1-5 * *", aCoversB: true},
+		{name: "more days", a: "09:00-17:00 1-6 * *", b: "09:00-17:00 1-5 * *", aCoversB: true},
+		{name: "last day of month", a: "* * 28-31 2; * * 30 4,6,9,11; * * 31 1,3,5,7,8,10,12", b: "* * L *", aCoversB: true},
+		{name: "half-open end", a: "09:00-17:00 * * *", b: "09:00-17:00) * * *", aCoversB: true},
+		{name: "overlapping", a: "09:00-12:00 * * *", b: "11:00-14:00 * * *"},
+		{name: "empty", a: "", b: "09:00-17:00 * * *", bCoversA: true},
+	}
+
+	parse := func(t *testing.T, expr string) []Rule {
+		if expr == "" {
+			return nil
+		}
+		rules, err := Parse(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rules
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := parse(t, tt.a), parse(t, tt.b)
+			if got := Equal(a, b); got != tt.equal {
+				t.Errorf("Equal(a, b) = %v, want %v", got, tt.equal)
+			}
+			if got := Equal(b, a); got != tt.equal {
+				t.Errorf("Equal(b, a) = %v, want %v", got, tt.equal)
+			}
+			if got := Covers(a, b); got != tt.aCoversB {
+				t.Errorf("Covers(a, b) = %v, want %v", got, tt.aCoversB)
+			}
+			if got := Covers(b, a); got != tt.bCoversA {
+				t.Errorf("Covers(b, a) = %v, want %v", got, tt.bCoversA)
+			}
+		})
+	}
+}
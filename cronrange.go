@@ -2,16 +2,32 @@
 // Unlike traditional crontab that defines specific moments in time, cronrange
 // defines time periods when something should be active.
 //
-// Format: `time dow dom month`
+// Format: `[!][TZ=zone] [DST=wall|elapsed] [DAYS=and|or] [CAL=name] time dow dom month [dates]`
 //
 // Where:
-//   - time:  Time range in 24h format (HH:MM[:SS]-HH:MM[:SS]) or * for all day
-//   - dow:   Day of week (0-6, where 0=Sunday)
-//   - dom:   Day of month (1-31)
-//   - month: Month (1-12)
+//   - !:     Optional exclusion mark, the time must not match such a rule
+//   - TZ:    Optional IANA timezone the rule is evaluated in, e.g. TZ=Europe/Berlin
+//   - DST:   Optional policy for days when the clock skips or repeats an hour, wall (default)
+//     matches wall-clock readings, elapsed keeps the nominal length of the range
+//   - DAYS:  Optional semantics between dow and dom, and (default) requires both to match,
+//     or matches either of them if both are restricted, like classic cron
+//   - CAL:   Optional name of a calendar registered with RegisterCalendar, used by H in dom
+//   - time:  Time range in 24h format (HH:MM[:SS]-HH:MM[:SS]) or * for all day,
+//     the end is inclusive unless followed by ")", e.g. 09:00-17:00)
+//   - dow:   Day of week (0-6, where 0=Sunday) or name (sun-sat), d#n (n-th weekday d of month)
+//     or dL (last weekday d of month)
+//   - dom:   Day of month (1-31), L (last day), L-n, nW (nearest weekday), LW (last weekday)
+//     or H (holiday of the rule's calendar)
+//   - month: Month (1-12) or name (jan-dec)
+//   - dates: Optional years (2026, 2026-2027), dates (2026-12-25) and date ranges
+//     (2026-12-20..2027-01-03) the rule is limited to
 //
-// Each field (except time) supports single values, lists (1,3,5), ranges (1-5)
-// and asterisk (*) for any/all values. Multiple rules can be combined using semicolons.
+// Each field (except time) supports single values, lists (1,3,5), ranges (1-5),
+// steps (*/2, 1-31/7) and asterisk (*) for any/all values. Multiple rules can be
+// combined using semicolons.
+//
+// Overnight time ranges, e.g. 22:00-02:00, belong to the day they start on, so dow, dom
+// and month fields are checked against the start day.
 //
 // Examples:
 //
@@ -23,65 +39,134 @@
 package cronrange
 
 import (
-	"bufio"
-	"bytes"
-	"fmt"
+	"errors"
 	"io"
+	"iter"
 	"strings"
 	"time"
 )
 
-// Parse parses a cronrange expression and returns a Rule slice
+// Parse parses a cronrange expression and returns a Rule slice.
+// Errors in rules are reported as *ParseError.
 func Parse(expr string) ([]Rule, error) {
-	rules := strings.Split(expr, ";")
-	result := make([]Rule, 0, len(rules))
-
-	for _, r := range rules {
-		rule, err := parseRule(strings.TrimSpace(r))
-		if err != nil {
-			return nil, fmt.Errorf("invalid rule %q: %w", r, err)
-		}
-		result = append(result, rule)
-	}
-
-	return result, nil
+	return parseExpr(expr, nil)
 }
 
-// ParseFromReader parses a cronrange expression from a reader and returns a Rule slice
-func ParseFromReader(rdr io.Reader) ([]Rule, error) {
-	buf, err := io.ReadAll(rdr)
+// parseExpr parses rules separated by semicolons. Rules can be references to named rule sets, @name
+// or !@name, if sets are given. Errors are reported as *ParseError with offsets in expr.
+func parseExpr(expr string, sets map[string][]Rule) ([]Rule, error) {
+	items, err := parseItems(expr, sets)
 	if err != nil {
-		return nil, fmt.Errorf("can't read from reader: %w", err)
+		return nil, err
 	}
-	if len(buf) == 0 {
-		return []Rule{}, nil
+	if err := checkScopes(items); err != nil {
+		return nil, err
 	}
+	return joinItems(items), nil
+}
+
+// exprItem is a semicolon-separated part of an expression, a rule or a reference to a rule set
+type exprItem struct {
+	text  string // the trimmed part
+	pos   int    // byte offset of text in the expression
+	ref   bool   // reference to a rule set, @name
+	rules []Rule // the rule or the rules of the referenced set
+}
+
+// parseItems parses semicolon-separated parts of an expression. Errors are reported as *ParseError
+// with the index of the part and offsets in expr.
+func parseItems(expr string, sets map[string][]Rule) ([]exprItem, error) {
+	parts := strings.Split(expr, ";")
+	result := make([]exprItem, 0, len(parts))
 
-	var res []Rule
-	scanner := bufio.NewScanner(bytes.NewReader(buf))
-	for scanner.Scan() {
-		rule := strings.TrimSpace(scanner.Text())
-		if rule == "" {
-			continue
+	offset := 0
+	for _, r := range parts {
+		trimmed := strings.TrimSpace(r)
+		item := exprItem{text: trimmed, pos: offset + strings.Index(r, trimmed)}
+		var err error
+		if strings.HasPrefix(strings.TrimPrefix(trimmed, "!"), "@") {
+			item.ref = strings.HasPrefix(trimmed, "@")
+			item.rules, err = resolveReference(trimmed, sets)
+		} else {
+			var rule Rule
+			rule, err = parseRule(trimmed)
+			item.rules = []Rule{rule}
 		}
-		r, err := Parse(rule)
 		if err != nil {
-			return nil, fmt.Errorf("invalid rule %q: %w", rule, err)
+			var pe *ParseError
+			if errors.As(err, &pe) {
+				// make the error relative to the whole expression
+				pe.Rule = len(result)
+				pe.Offset += item.pos
+			}
+			return nil, err
 		}
-		res = append(res, r...)
+		result = append(result, item)
+		offset += len(r) + 1
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading input: %w", err)
+
+	return result, nil
+}
+
+// joinItems returns the rules of all parts in order
+func joinItems(items []exprItem) []Rule {
+	res := make([]Rule, 0, len(items))
+	for _, item := range items {
+		res = append(res, item.rules...)
 	}
-	return res, nil
+	return res
+}
+
+// ParseFromReader parses cronrange rules from a reader and returns a Rule slice. Every line holds one
+// or more rules separated by semicolons. Empty lines are skipped, # starts a comment if it begins the line
+// or follows whitespace, and a line ending with \ continues on the next one.
+// Lines like "name = rules" define named rule sets instead, which following lines can reference
+// with @name, see ParseDefinitions. Only rules outside of definitions are returned.
+// Errors in rules are reported as *ParseError with the rule index, the line number and the offset in the line.
+func ParseFromReader(rdr io.Reader) ([]Rule, error) {
+	rules, _, err := parseFile(rdr, false)
+	return rules, err
 }
 
-// Match checks if the given time matches any of the rules
+// Match checks if the given time matches any of the rules and none of the exclusion rules
 func Match(rules []Rule, t time.Time) bool {
+	matched := false
 	for _, rule := range rules {
-		if rule.matches(t) {
-			return true
+		switch {
+		case rule.exclude:
+			if rule.matches(t) {
+				return false
+			}
+		case !matched:
+			matched = rule.matches(t)
 		}
 	}
-	return false
+	return matched
+}
+
+// Intervals returns an iterator over merged, non-overlapping intervals when any of the rules is active
+// and none of the exclusion rules is, within [from, to). Intervals are yielded in chronological order
+// and clipped to from and to, adjacent and overlapping windows of different rules are merged into
+// a single interval. Intervals are reported in the location of from, rules without a timezone are
+// evaluated in it as well.
+func Intervals(rules []Rule, from, to time.Time) iter.Seq[Interval] {
+	return intervalsOf(activeSpans(rules, from, to), from, to)
+}
+
+// NextStart returns the moment strictly after the given time when the rules become active.
+// A window already active at the given time is skipped and the start of the following one is returned.
+// Rules without a timezone are evaluated in the location of the given time. The second value is false
+// if the rules never become active within the search horizon of 400 years.
+func NextStart(rules []Rule, after time.Time) (time.Time, bool) {
+	return nextStart(activeSpans(rules, after, after.AddDate(searchHorizonYears, 0, 0)), after)
+}
+
+// NextEnd returns the moment strictly after the given time when the rules stop being active.
+// If the rules are active at the given time, it is the end of the current window, otherwise the end
+// of the next one. Rules without a timezone are evaluated in the location of the given time.
+// The second value is false if the rules never stop being active within the search horizon
+// of 400 years, e.g. for "* * * *".
+func NextEnd(rules []Rule, after time.Time) (time.Time, bool) {
+	horizon := after.AddDate(searchHorizonYears, 0, 0)
+	return nextEnd(activeSpans(rules, after, horizon), after, horizon)
 }
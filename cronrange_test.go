@@ -38,6 +38,91 @@ func TestParse(t *testing.T) {
 			expr: "09:00-17:00 1-5 * 4-9",
 			want: "09:00-17:00 1-5 * 4-9",
 		},
+		{
+			name: "day and month names",
+			expr: "09:00-17:00 Mon-Fri * jan-mar,DEC; * sat,sun * *",
+			want: "09:00-17:00 1-5 * 1-3,12; * 0,6 * *",
+		},
+		{
+			name: "steps",
+			expr: "* * 1-7 */2; 12:00-13:00 * 1-31/7 *",
+			want: "* * 1-7 */2; 12:00-13:00 * */7 *",
+		},
+		{
+			name: "day specifiers",
+			expr: "* * L-2,LW *; 18:00-22:00 tue#2 * *; * 5L * *",
+			want: "* * L-2,LW *; 18:00-22:00 2#2 * *; * 5L * *",
+		},
+		{
+			name: "dates field",
+			expr: "09:00-17:00 * * * 2026-12-20..2027-01-03; * 1-5 * * 2026-2027; * * * * *",
+			want: "09:00-17:00 * * * 2026-12-20..2027-01-03; * 1-5 * * 2026-2027; * * * *",
+		},
+		{
+			name:    "invalid dates field",
+			expr:    "09:00-17:00 * * * 2026-12-32",
+			wantErr: true,
+		},
+		{
+			name:    "too many fields",
+			expr:    "09:00-17:00 * * * 2026 2027",
+			wantErr: true,
+		},
+		{
+			name: "exclusion rules",
+			expr: "09:00-17:00 1-5 * *; !12:00-13:00) * * *; ! TZ=UTC * * 25 12",
+			want: "09:00-17:00 1-5 * *; !12:00-13:00) * * *; !TZ=UTC * * 25 12",
+		},
+		{
+			name: "with timezone",
+			expr: "TZ=Europe/Berlin 09:00-17:00 1-5 * *; * 0,6 * *",
+			want: "TZ=Europe/Berlin 09:00-17:00 1-5 * *; * 0,6 * *",
+		},
+		{
+			name: "half-open ranges",
+			expr: "09:00-12:00) * * *; 12:00-17:00) 1-5 * *",
+			want: "09:00-12:00) * * *; 12:00-17:00) 1-5 * *",
+		},
+		{
+			name: "with DST policy",
+			expr: "DST=elapsed TZ=America/New_York 01:00-03:00 * * *; DST=wall 01:00-03:00 * * *",
+			want: "TZ=America/New_York DST=elapsed 01:00-03:00 * * *; 01:00-03:00 * * *",
+		},
+		{
+			name: "with days semantics",
+			expr: "DAYS=or * 1 13 *; DAYS=and * 1 13 *",
+			want: "DAYS=or * 1 13 *; * 1 13 *",
+		},
+		{
+			name:    "invalid days semantics",
+			expr:    "DAYS=xor * 1 13 *",
+			wantErr: true,
+		},
+		{
+			name:    "invalid DST policy",
+			expr:    "DST=none 01:00-03:00 * * *",
+			wantErr: true,
+		},
+		{
+			name:    "invalid timezone",
+			expr:    "TZ=Mars/Olympus 09:00-17:00 1-5 * *",
+			wantErr: true,
+		},
+		{
+			name:    "empty timezone",
+			expr:    "TZ= 09:00-17:00 1-5 * *",
+			wantErr: true,
+		},
+		{
+			name:    "unknown option",
+			expr:    "XX=1 09:00-17:00 1-5 * *",
+			wantErr: true,
+		},
+		{
+			name:    "timezone without fields",
+			expr:    "TZ=UTC",
+			wantErr: true,
+		},
 		{
 			name:    "invalid time format",
 			expr:    "1720-2135 1-5 * *",
@@ -157,6 +242,60 @@ func TestMatch(t *testing.T) {
 			time: time.Date(2024, 1, 15, 12, 30, 0, 0, time.UTC), // 15th at 12:30
 			want: true,
 		},
+		{
+			name: "timezone rule converts time",
+			expr: "TZ=America/New_York 09:00-17:00 1-5 * *",
+			time: time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC), // Monday 9:00 AM in New York
+			want: true,
+		},
+		{
+			name: "timezone rule ignores location of time",
+			expr: "TZ=America/New_York 09:00-17:00 1-5 * *",
+			time: time.Date(2024, 1, 1, 9, 0, 0, 0, time.FixedZone("X", 5*3600)), // 11:00 PM Sunday in New York
+			want: false,
+		},
+		{
+			name: "within date range",
+			expr: "09:00-17:00 * * * 2026-12-20..2027-01-03",
+			time: time.Date(2027, 1, 2, 12, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name: "outside date range",
+			expr: "09:00-17:00 * * * 2026-12-20..2027-01-03",
+			time: time.Date(2027, 1, 4, 12, 0, 0, 0, time.UTC),
+			want: false,
+		},
+		{
+			name: "outside year",
+			expr: "* * * * 2026",
+			time: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: false,
+		},
+		{
+			name: "excluded lunch",
+			expr: "09:00-17:00 1-5 * *; !12:00-13:00) * * *",
+			time: time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC),
+			want: false,
+		},
+		{
+			name: "after excluded lunch",
+			expr: "09:00-17:00 1-5 * *; !12:00-13:00) * * *",
+			time: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name: "exclusion before positive rule",
+			expr: "!* * 1 1; * * * *",
+			time: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC),
+			want: false,
+		},
+		{
+			name: "only exclusion rules",
+			expr: "!* * 1 1",
+			time: time.Date(2024, 1, 2, 13, 0, 0, 0, time.UTC),
+			want: false,
+		},
 		{
 			name: "specific month days non-match",
 			expr: "12:00-13:00 * 1,15 *",
@@ -222,6 +361,37 @@ func TestParseFromReader(t *testing.T) {
 			input:   "invalid rule",
 			wantErr: true,
 		},
+		{
+			name: "comments and blank lines",
+			input: "# business hours\n\n17:20-21:35 1-5 * *          # Weekdays\n   \n\t# weekends\n" +
+				"* 0,6 * *\t# all day\n",
+			want: []string{"17:20-21:35 1-5 * *", "* 0,6 * *"},
+		},
+		{
+			name:  "day specifier is not a comment",
+			input: "09:00-17:00 5#3,1L * * # third Friday and last Monday",
+			want:  []string{"09:00-17:00 1L,5#3 * *"},
+		},
+		{
+			name:  "only comments",
+			input: "# nothing here\n\n# and here",
+			want:  []string{},
+		},
+		{
+			name:  "continuation",
+			input: "TZ=UTC \\\n  09:00-17:00 \\  # business hours\n  1-5 * *; \\\n  * 0,6 * *\n12:00-13:00 * 1 *",
+			want:  []string{"TZ=UTC 09:00-17:00 1-5 * *", "* 0,6 * *", "12:00-13:00 * 1 *"},
+		},
+		{
+			name:  "continuation at the end",
+			input: "* 0 * * \\",
+			want:  []string{"* 0 * *"},
+		},
+		{
+			name:    "comment without whitespace",
+			input:   "* 0 * *# comment",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -246,3 +416,357 @@ func TestParseFromReader(t *testing.T) {
 		})
 	}
 }
+
+func TestNextStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		expr   string
+		after  time.Time
+		want   time.Time
+		wantOk bool
+	}{
+		{
+			name:   "later today",
+			expr:   "17:20-21:35 1-5 * *",
+			after:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), // Monday noon
+			want:   time.Date(2024, 1, 1, 17, 20, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "active now, next window on the following day",
+			expr:   "17:20-21:35 1-5 * *",
+			after:  time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 1, 2, 17, 20, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "exactly at start is skipped",
+			expr:   "17:20-21:35 1-5 * *",
+			after:  time.Date(2024, 1, 1, 17, 20, 0, 0, time.UTC),
+			want:   time.Date(2024, 1, 2, 17, 20, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "skips weekend",
+			expr:   "09:00-17:00 1-5 * *",
+			after:  time.Date(2024, 1, 5, 18, 0, 0, 0, time.UTC), // Friday evening
+			want:   time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "multiple rules, earliest wins",
+			expr:   "09:00-17:00 1-5 * *; 10:00-12:00 0,6 * *",
+			after:  time.Date(2024, 1, 5, 18, 0, 0, 0, time.UTC), // Friday evening
+			want:   time.Date(2024, 1, 6, 10, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "overnight range",
+			expr:   "23:00-02:00 * * *",
+			after:  time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "sparse dom and month",
+			expr:   "12:00-13:00 * 29 2",
+			after:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			want:   time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "adjacent rules merge into one window",
+			expr:   "09:00-11:59:59 * * *; 12:00-17:00 * * *",
+			after:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "rule with timezone",
+			expr:   "TZ=Asia/Tokyo 09:00-17:00 1-5 * *",
+			after:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), // Monday 9 PM in Tokyo
+			want:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),  // Tuesday 9 AM in Tokyo
+			wantOk: true,
+		},
+		{
+			name:   "patch tuesday",
+			expr:   "18:00-22:00 2#2 * *",
+			after:  time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 2, 13, 18, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "last day of month",
+			expr:   "* * L *",
+			after:  time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "future date range",
+			expr:   "* * * * 2026-12-20..2027-01-03",
+			after:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			want:   time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "past date range",
+			expr:   "* * * * 2020-12-20..2021-01-03",
+			after:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			wantOk: false,
+		},
+		{
+			name:   "always active",
+			expr:   "* * * *",
+			after:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			wantOk: false,
+		},
+		{
+			name:   "never active",
+			expr:   "* * 31 2",
+			after:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, ok := NextStart(rules, tt.after)
+			if ok != tt.wantOk {
+				t.Fatalf("NextStart() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("NextStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextEnd(t *testing.T) {
+	tests := []struct {
+		name   string
+		expr   string
+		after  time.Time
+		want   time.Time
+		wantOk bool
+	}{
+		{
+			name:   "active now",
+			expr:   "17:20-21:35 1-5 * *",
+			after:  time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 1, 1, 21, 35, 1, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "inactive now, end of the next window",
+			expr:   "17:20-21:35 1-5 * *",
+			after:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 1, 1, 21, 35, 1, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "overnight range ends next day",
+			expr:   "23:00-02:00 * * *",
+			after:  time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC),
+			want:   time.Date(2024, 1, 2, 2, 0, 1, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "overnight range attributed to the start day",
+			expr:   "22:00-02:00 5 * *",
+			after:  time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC), // Friday noon
+			want:   time.Date(2024, 1, 6, 2, 0, 1, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "all weekend",
+			expr:   "* 0,6 * *",
+			after:  time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC), // Saturday noon
+			want:   time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "overlapping rules",
+			expr:   "09:00-12:00 * * *; 11:00-15:00 * * *",
+			after:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 1, 1, 15, 0, 1, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "all days of a date range",
+			expr:   "* * * * 2026-12-20..2027-01-03",
+			after:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			want:   time.Date(2027, 1, 4, 0, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "exclusion ends the window early",
+			expr:   "09:00-17:00 * * *; !12:00-13:00) * * *",
+			after:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "always active",
+			expr:   "* * * *",
+			after:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, ok := NextEnd(rules, tt.after)
+			if ok != tt.wantOk {
+				t.Fatalf("NextEnd() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("NextEnd() = %v, want %v", got, tt.want)
+			}
+			if ok && Match(rules, got) {
+				t.Errorf("Match() at NextEnd() = true, want false")
+			}
+		})
+	}
+}
+
+func TestIntervals(t *testing.T) {
+	d := func(day, h, m, s int) time.Time { return time.Date(2024, 1, day, h, m, s, 0, time.UTC) }
+	tests := []struct {
+		name     string
+		expr     string
+		from, to time.Time
+		want     []Interval
+	}{
+		{
+			name: "weekday windows",
+			expr: "09:00-17:00 1-5 * *",
+			from: d(5, 0, 0, 0), // Friday
+			to:   d(9, 0, 0, 0),
+			want: []Interval{
+				{Start: d(5, 9, 0, 0), End: d(5, 17, 0, 1)},
+				{Start: d(8, 9, 0, 0), End: d(8, 17, 0, 1)},
+			},
+		},
+		{
+			name: "clipped to period",
+			expr: "09:00-17:00 * * *",
+			from: d(1, 12, 0, 0),
+			to:   d(2, 10, 30, 0),
+			want: []Interval{
+				{Start: d(1, 12, 0, 0), End: d(1, 17, 0, 1)},
+				{Start: d(2, 9, 0, 0), End: d(2, 10, 30, 0)},
+			},
+		},
+		{
+			name: "overnight windows merge across midnight",
+			expr: "23:00-02:00 * * *",
+			from: d(1, 12, 0, 0),
+			to:   d(3, 12, 0, 0),
+			want: []Interval{
+				{Start: d(1, 23, 0, 0), End: d(2, 2, 0, 1)},
+				{Start: d(2, 23, 0, 0), End: d(3, 2, 0, 1)},
+			},
+		},
+		{
+			name: "overlapping and adjacent rules merge",
+			expr: "09:00-12:00 * * *; 11:00-14:59:59 * * *; 15:00-16:00 * * *; 18:00-19:00 * * *",
+			from: d(1, 0, 0, 0),
+			to:   d(2, 0, 0, 0),
+			want: []Interval{
+				{Start: d(1, 9, 0, 0), End: d(1, 16, 0, 1)},
+				{Start: d(1, 18, 0, 0), End: d(1, 19, 0, 1)},
+			},
+		},
+		{
+			name: "half-open ranges tile without overlap",
+			expr: "09:00-12:00) * * *; 12:00-17:00) * * *",
+			from: d(1, 0, 0, 0),
+			to:   d(2, 0, 0, 0),
+			want: []Interval{
+				{Start: d(1, 9, 0, 0), End: d(1, 17, 0, 0)},
+			},
+		},
+		{
+			name: "business hours minus lunch and holidays",
+			expr: "09:00-17:00) 1-5 * *; !12:00-13:00) * * *; !* * 1 1",
+			from: d(1, 0, 0, 0), // Monday, New Year
+			to:   d(3, 0, 0, 0),
+			want: []Interval{
+				{Start: d(2, 9, 0, 0), End: d(2, 12, 0, 0)},
+				{Start: d(2, 13, 0, 0), End: d(2, 17, 0, 0)},
+			},
+		},
+		{
+			name: "whole weekend",
+			expr: "* 0,6 * *",
+			from: d(1, 0, 0, 0),
+			to:   d(15, 0, 0, 0),
+			want: []Interval{
+				{Start: d(6, 0, 0, 0), End: d(8, 0, 0, 0)},
+				{Start: d(13, 0, 0, 0), End: d(15, 0, 0, 0)},
+			},
+		},
+		{
+			name: "rules in different timezones",
+			expr: "TZ=Europe/Berlin 09:00-17:00 * * *; TZ=America/New_York 09:00-17:00 * * *",
+			from: d(1, 0, 0, 0),
+			to:   d(2, 0, 0, 0),
+			want: []Interval{
+				{Start: d(1, 8, 0, 0), End: d(1, 22, 0, 1)},
+			},
+		},
+		{
+			name: "empty period",
+			expr: "* * * *",
+			from: d(2, 0, 0, 0),
+			to:   d(1, 0, 0, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			var got []Interval
+			for iv := range Intervals(rules, tt.from, tt.to) {
+				got = append(got, iv)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Intervals() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i].Start) || !got[i].End.Equal(tt.want[i].End) {
+					t.Errorf("Intervals()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	t.Run("early stop", func(t *testing.T) {
+		rules, err := Parse("09:00-17:00 * * *")
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		count := 0
+		for range Intervals(rules, d(1, 0, 0, 0), d(31, 0, 0, 0)) {
+			count++
+			if count == 3 {
+				break
+			}
+		}
+		if count != 3 {
+			t.Errorf("got %d intervals, want 3", count)
+		}
+	})
+}
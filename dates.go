@@ -0,0 +1,142 @@
+package cronrange
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout is the format of absolute dates in the dates field
+const dateLayout = "2006-01-02"
+
+// DateRange is a period of calendar days the rule is limited to. From and To are midnight UTC
+// of the first and the last day, both days are included.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// dateRange is a period of calendar days, with both days given as days since 1970-01-01 and included
+type dateRange struct {
+	from, to int64
+}
+
+// parseDates parses the optional dates field: a list of years (2026), year ranges (2026-2027),
+// dates (2026-12-25) and date ranges (2026-12-20..2027-01-03), or a single asterisk for no limit.
+// Ranges are returned sorted by their first day.
+func parseDates(s string) ([]dateRange, error) {
+	if s == "*" {
+		return nil, nil
+	}
+
+	var res []dateRange
+	pos := 0
+	for _, part := range strings.Split(s, ",") {
+		dr, err := parseDateRange(part)
+		if err != nil {
+			return nil, atPart(pos, part, err)
+		}
+		res = append(res, dr)
+		pos += len(part) + 1
+	}
+
+	sortDates(res)
+	return res, nil
+}
+
+// sortDates sorts date ranges by their first day
+func sortDates(dates []dateRange) {
+	sort.Slice(dates, func(i, j int) bool { return dates[i].from < dates[j].from })
+}
+
+// parseDateRange parses a single item of the dates field
+func parseDateRange(s string) (dateRange, error) {
+	if from, to, ok := strings.Cut(s, ".."); ok {
+		start, err := time.Parse(dateLayout, from)
+		if err != nil {
+			return dateRange{}, badFormat("invalid date %q", from)
+		}
+		end, err := time.Parse(dateLayout, to)
+		if err != nil {
+			return dateRange{}, badFormat("invalid date %q", to)
+		}
+		if end.Before(start) {
+			return dateRange{}, outOfRange("date range %q ends before it starts", s)
+		}
+		return dateRange{from: dayNumber(start, time.UTC), to: dayNumber(end, time.UTC)}, nil
+	}
+
+	if len(s) == len(dateLayout) {
+		day, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return dateRange{}, badFormat("invalid date %q", s)
+		}
+		days := dayNumber(day, time.UTC)
+		return dateRange{from: days, to: days}, nil
+	}
+
+	first, last, isRange := strings.Cut(s, "-")
+	startYear, err := parseYear(first)
+	if err != nil {
+		return dateRange{}, err
+	}
+	endYear := startYear
+	if isRange {
+		if endYear, err = parseYear(last); err != nil {
+			return dateRange{}, err
+		}
+		if endYear < startYear {
+			return dateRange{}, outOfRange("year range %q ends before it starts", s)
+		}
+	}
+	return dateRange{
+		from: dayNumber(time.Date(startYear, time.January, 1, 0, 0, 0, 0, time.UTC), time.UTC),
+		to:   dayNumber(time.Date(endYear, time.December, 31, 0, 0, 0, 0, time.UTC), time.UTC),
+	}, nil
+}
+
+// parseYear parses a four-digit year
+func parseYear(s string) (int, error) {
+	year, err := strconv.Atoi(s)
+	if err != nil || len(s) != 4 {
+		return 0, badFormat("invalid year %q", s)
+	}
+	if year < 1 {
+		return 0, outOfRange("invalid year %q", s)
+	}
+	return year, nil
+}
+
+// contains checks if the calendar day, given as days since 1970-01-01, is within the range
+func (dr dateRange) contains(days int64) bool {
+	return days >= dr.from && days <= dr.to
+}
+
+// String returns the string representation of a dateRange, using years where possible
+func (dr dateRange) String() string {
+	from, to := civilFromDays(dr.from), civilFromDays(dr.to)
+	if from.month == time.January && from.day == 1 && to.month == time.December && to.day == 31 {
+		if from.year == to.year {
+			return strconv.Itoa(from.year)
+		}
+		return fmt.Sprintf("%d-%d", from.year, to.year)
+	}
+	if dr.from == dr.to {
+		return from.time().Format(dateLayout)
+	}
+	return from.time().Format(dateLayout) + ".." + to.time().Format(dateLayout)
+}
+
+// formatDates returns the string representation of the dates field
+func formatDates(dates []dateRange) string {
+	if len(dates) == 0 {
+		return "*"
+	}
+	res := make([]string, 0, len(dates))
+	for _, dr := range dates {
+		res = append(res, dr.String())
+	}
+	return strings.Join(res, ",")
+}
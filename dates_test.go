@@ -0,0 +1,67 @@
+package cronrange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDates(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{name: "no limit", s: "*", want: "*"},
+		{name: "year", s: "2026", want: "2026"},
+		{name: "year range", s: "2026-2027", want: "2026-2027"},
+		{name: "single date", s: "2026-12-25", want: "2026-12-25"},
+		{name: "date range", s: "2026-12-20..2027-01-03", want: "2026-12-20..2027-01-03"},
+		{name: "date range of whole years", s: "2026-01-01..2027-12-31", want: "2026-2027"},
+		{name: "sorted list", s: "2027-12-25,2026,2026-12-20..2027-01-03", want: "2026,2026-12-20..2027-01-03,2027-12-25"},
+		{name: "invalid year", s: "26", wantErr: true},
+		{name: "invalid year range", s: "2027-2026", wantErr: true},
+		{name: "invalid date", s: "2026-02-30", wantErr: true},
+		{name: "invalid range start", s: "2026-13-01..2026-12-31", wantErr: true},
+		{name: "invalid range end", s: "2026-12-01..2026-12-32", wantErr: true},
+		{name: "reversed date range", s: "2027-01-03..2026-12-20", wantErr: true},
+		{name: "garbage", s: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDates(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if s := formatDates(got); s != tt.want {
+				t.Errorf("parseDates() = %v, want %v", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateRangeContains(t *testing.T) {
+	dr, err := parseDateRange("2026-12-20..2027-01-03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		day  time.Time
+		want bool
+	}{
+		{time.Date(2026, 12, 19, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2027, 1, 3, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2027, 1, 4, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := dr.contains(dayNumber(tt.day, time.UTC)); got != tt.want {
+			t.Errorf("contains(%s) = %v, want %v", tt.day.Format(dateLayout), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package cronrange
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// daySpec is a day specifier depending on the month, like last day of month or third Friday
+type daySpec struct {
+	kind  daySpecKind
+	value int // day of month for nearestWeekday, offset for lastDay, weekday for nthWeekday and lastWeekday
+	nth   int // occurrence of the weekday in the month for nthWeekday
+}
+
+type daySpecKind int
+
+const (
+	lastDay            daySpecKind = iota // L or L-n, last day of month or n days before it
+	nearestWeekday                        // nW, weekday nearest to day n within the same month
+	lastMonthWeekday                      // LW, last weekday (Mon-Fri) of month
+	nthWeekday                            // d#n, n-th weekday d of month
+	lastWeekdayOfMonth                    // dL, last weekday d of month
+	holiday                               // H, holiday of the rule's calendar
+)
+
+// parseDomSpec parses a dom specifier: L, L-n, LW, nW or H. It returns false if s is not a specifier.
+func parseDomSpec(s string) (daySpec, bool, error) {
+	u := strings.ToUpper(s)
+	switch {
+	case u == "H":
+		return daySpec{kind: holiday}, true, nil
+	case u == "L":
+		return daySpec{kind: lastDay}, true, nil
+	case u == "LW":
+		return daySpec{kind: lastMonthWeekday}, true, nil
+	case strings.HasPrefix(u, "L-"):
+		n, err := strconv.Atoi(u[2:])
+		if err != nil {
+			return daySpec{}, true, badFormat("invalid last day offset %q", s)
+		}
+		if n < 0 || n > 30 {
+			return daySpec{}, true, outOfRange("last day offset out of range")
+		}
+		return daySpec{kind: lastDay, value: n}, true, nil
+	case strings.HasSuffix(u, "W"):
+		n, err := strconv.Atoi(u[:len(u)-1])
+		if err != nil {
+			return daySpec{}, true, badFormat("invalid nearest weekday %q", s)
+		}
+		if n < 1 || n > 31 {
+			return daySpec{}, true, outOfRange("nearest weekday day out of range")
+		}
+		return daySpec{kind: nearestWeekday, value: n}, true, nil
+	}
+	return daySpec{}, false, nil
+}
+
+// parseDowSpec parses a dow specifier: d#n or dL, where d is a weekday number or name.
+// It returns false if s is not a specifier.
+func parseDowSpec(s string) (daySpec, bool, error) {
+	if day, nth, ok := strings.Cut(s, "#"); ok {
+		wd, err := parseWeekday(day)
+		if err != nil {
+			return daySpec{}, true, err
+		}
+		n, err := strconv.Atoi(nth)
+		if err != nil {
+			return daySpec{}, true, badFormat("invalid weekday occurrence %q, must be 1-5", nth)
+		}
+		if n < 1 || n > 5 {
+			return daySpec{}, true, outOfRange("invalid weekday occurrence %q, must be 1-5", nth)
+		}
+		return daySpec{kind: nthWeekday, value: wd, nth: n}, true, nil
+	}
+
+	if len(s) > 1 && (s[len(s)-1] == 'L' || s[len(s)-1] == 'l') {
+		wd, err := parseWeekday(s[:len(s)-1])
+		if err != nil {
+			return daySpec{}, true, err
+		}
+		return daySpec{kind: lastWeekdayOfMonth, value: wd}, true, nil
+	}
+	return daySpec{}, false, nil
+}
+
+// parseWeekday parses a weekday number or name of a dow specifier
+func parseWeekday(s string) (int, error) {
+	wd, err := parseFieldValue(s, dowNames)
+	if err != nil {
+		return 0, badFormat("invalid weekday %q", s)
+	}
+	if wd < 0 || wd > 6 {
+		return 0, outOfRange("invalid weekday %q", s)
+	}
+	return wd, nil
+}
+
+// parseSpecField parses a field which may contain day specifiers among other values.
+// Specifiers are recognized by parseSpec, the rest is parsed by parseField.
+func parseSpecField(s string, min, max int, names []string,
+	parseSpec func(string) (daySpec, bool, error)) (Field, error) {
+	var specs []daySpec
+	var rest []string
+	var restPos []int // offsets of rest parts in s
+	pos := 0
+	for _, part := range strings.Split(s, ",") {
+		spec, ok, err := parseSpec(part)
+		if err != nil {
+			return Field{}, atPart(pos, part, err)
+		}
+		partPos := pos
+		pos += len(part) + 1
+		if !ok {
+			rest = append(rest, part)
+			restPos = append(restPos, partPos)
+			continue
+		}
+		if !containsSpec(specs, spec) {
+			specs = append(specs, spec)
+		}
+	}
+
+	if len(rest) == 0 {
+		return Field{names: names, min: min, max: max, specs: specs}, nil
+	}
+	joined := strings.Join(rest, ",")
+	f, err := parseField(joined, min, max, names)
+	if err != nil {
+		var pe *partError
+		if errors.As(err, &pe) {
+			// point to the part in s rather than in the joined rest
+			idx := strings.Count(joined[:pe.pos], ",")
+			return Field{}, &partError{pos: restPos[idx], text: pe.text, err: pe.err}
+		}
+		return Field{}, err
+	}
+	if !f.all {
+		f.specs = specs
+	}
+	return f, nil
+}
+
+// containsSpec checks if the spec is in the list
+func containsSpec(specs []daySpec, spec daySpec) bool {
+	for _, s := range specs {
+		if s == spec {
+			return true
+		}
+	}
+	return false
+}
+
+// matches checks if the calendar day satisfies the specifier.
+// The calendar is used by holiday specifiers and can be nil.
+func (s daySpec) matches(day civil, cal Calendar) bool {
+	last := daysIn(day.year, day.month)
+	switch s.kind {
+	case lastDay:
+		return day.day == last-s.value
+	case nearestWeekday:
+		return s.value <= last && day.day == nearestWeekdayOf(day, s.value)
+	case lastMonthWeekday:
+		return day.day == nearestWeekdayOf(day, last)
+	case nthWeekday:
+		return int(day.weekday) == s.value && (day.day-1)/7+1 == s.nth
+	case lastWeekdayOfMonth:
+		return int(day.weekday) == s.value && day.day+7 > last
+	case holiday:
+		return cal != nil && cal.IsHoliday(day.time())
+	}
+	return false
+}
+
+// format returns the string representation of the specifier, using weekday names if named is set
+func (s daySpec) format(named bool) string {
+	weekday := strconv.Itoa(s.value)
+	if named {
+		weekday = dowNames[s.value%7]
+	}
+
+	switch s.kind {
+	case lastDay:
+		if s.value == 0 {
+			return "L"
+		}
+		return fmt.Sprintf("L-%d", s.value)
+	case nearestWeekday:
+		return fmt.Sprintf("%dW", s.value)
+	case lastMonthWeekday:
+		return "LW"
+	case nthWeekday:
+		return fmt.Sprintf("%s#%d", weekday, s.nth)
+	case lastWeekdayOfMonth:
+		return weekday + "L"
+	case holiday:
+		return "H"
+	}
+	return ""
+}
+
+// nearestWeekdayOf returns the day of month of the weekday (Mon-Fri) nearest to the given day of month,
+// without crossing the month boundary. The month is the one of the calendar day passed as day.
+func nearestWeekdayOf(day civil, dom int) int {
+	wd := (int(day.weekday) + dom - day.day) % 7
+	if wd < 0 {
+		wd += 7
+	}
+	switch time.Weekday(wd) {
+	case time.Saturday:
+		if dom == 1 {
+			return 3 // the 1st is Saturday, the nearest weekday within the month is Monday the 3rd
+		}
+		return dom - 1
+	case time.Sunday:
+		if dom == daysIn(day.year, day.month) {
+			return dom - 2 // the last day is Sunday, the nearest weekday within the month is Friday
+		}
+		return dom + 1
+	}
+	return dom
+}
@@ -0,0 +1,140 @@
+package cronrange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSpecField(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		dow       bool
+		want      string
+		wantNamed string
+		wantErr   bool
+	}{
+		{name: "last day", s: "L", want: "L"},
+		{name: "last day lowercase", s: "l", want: "L"},
+		{name: "days before last", s: "L-2", want: "L-2"},
+		{name: "last weekday", s: "LW", want: "LW"},
+		{name: "nearest weekday", s: "15W", want: "15W"},
+		{name: "mixed with values", s: "L,1-3,15W,L", want: "1-3,15W,L"},
+		{name: "asterisk wins", s: "*,L", want: "*"},
+		{name: "nth weekday", s: "5#3", dow: true, want: "5#3", wantNamed: "fri#3"},
+		{name: "nth weekday by name", s: "Tue#2", dow: true, want: "2#2", wantNamed: "tue#2"},
+		{name: "last weekday of month", s: "5L", dow: true, want: "5L", wantNamed: "friL"},
+		{name: "weekday spec mixed with days", s: "fril,0,6", dow: true, want: "0,6,5L", wantNamed: "sun,sat,friL"},
+		{name: "invalid last day offset", s: "L-x", wantErr: true},
+		{name: "last day offset out of range", s: "L-31", wantErr: true},
+		{name: "nearest weekday out of range", s: "32W", wantErr: true},
+		{name: "invalid nearest weekday", s: "xW", wantErr: true},
+		{name: "last day in dow", s: "L", dow: true, wantErr: true},
+		{name: "nth weekday in dom", s: "5#3", wantErr: true},
+		{name: "nth weekday out of range", s: "5#6", dow: true, wantErr: true},
+		{name: "invalid weekday", s: "7#1", dow: true, wantErr: true},
+		{name: "invalid last weekday", s: "7L", dow: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Field
+			var err error
+			if tt.dow {
+				got, err = parseSpecField(tt.s, 0, 6, dowNames, parseDowSpec)
+			} else {
+				got, err = parseSpecField(tt.s, 1, 31, nil, parseDomSpec)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSpecField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("parseSpecField() = %v, want %v", got.String(), tt.want)
+			}
+			if tt.wantNamed != "" && got.Named() != tt.wantNamed {
+				t.Errorf("parseSpecField() named = %v, want %v", got.Named(), tt.wantNamed)
+			}
+		})
+	}
+}
+
+func TestDaySpecMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		spec daySpec
+		want []string // all matching days in the first half of 2024
+	}{
+		{
+			name: "last day",
+			spec: daySpec{kind: lastDay},
+			want: []string{"01-31", "02-29", "03-31", "04-30", "05-31", "06-30"},
+		},
+		{
+			name: "two days before last",
+			spec: daySpec{kind: lastDay, value: 2},
+			want: []string{"01-29", "02-27", "03-29", "04-28", "05-29", "06-28"},
+		},
+		{
+			name: "last weekday",
+			spec: daySpec{kind: lastMonthWeekday},
+			// Mar 31 and Jun 30 are Sundays, Jun 29 is Saturday
+			want: []string{"01-31", "02-29", "03-29", "04-30", "05-31", "06-28"},
+		},
+		{
+			name: "nearest weekday to 15th",
+			spec: daySpec{kind: nearestWeekday, value: 15},
+			// Jun 15 is Saturday
+			want: []string{"01-15", "02-15", "03-15", "04-15", "05-15", "06-14"},
+		},
+		{
+			name: "nearest weekday to 1st",
+			spec: daySpec{kind: nearestWeekday, value: 1},
+			// Jun 1 is Saturday, nearest weekday within June is Monday the 3rd
+			want: []string{"01-01", "02-01", "03-01", "04-01", "05-01", "06-03"},
+		},
+		{
+			name: "nearest weekday to 31st",
+			spec: daySpec{kind: nearestWeekday, value: 31},
+			// Mar 31 is Sunday, no 31st in Feb, Apr and Jun
+			want: []string{"01-31", "03-29", "05-31"},
+		},
+		{
+			name: "second Tuesday",
+			spec: daySpec{kind: nthWeekday, value: 2, nth: 2},
+			want: []string{"01-09", "02-13", "03-12", "04-09", "05-14", "06-11"},
+		},
+		{
+			name: "fifth Friday",
+			spec: daySpec{kind: nthWeekday, value: 5, nth: 5},
+			want: []string{"03-29", "05-31"},
+		},
+		{
+			name: "last Friday",
+			spec: daySpec{kind: lastWeekdayOfMonth, value: 5},
+			want: []string{"01-26", "02-23", "03-29", "04-26", "05-31", "06-28"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); day.Month() <= 6; day = day.AddDate(0, 0, 1) {
+				if tt.spec.matches(civilFromDays(dayNumber(day, time.UTC)), nil) {
+					got = append(got, day.Format("01-02"))
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("matching days = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("matching days = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
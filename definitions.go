@@ -0,0 +1,172 @@
+package cronrange
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// optionKeys are keys of rule options, which can't be used as names of rule sets
+var optionKeys = []string{"TZ", "DST", "DAYS", "CAL"}
+
+// ParseDefinitions parses named rule sets from a reader and returns them by name. Every line defines
+// a set, e.g. "business = 09:00-17:00 1-5 * *", with comments and continuation lines as in ParseFromReader.
+// Rules of a definition can reference sets defined above it with @name, e.g. "support = @business; * 0,6 * *",
+// and exclude all rules of a set with !@name. Exclusion rules apply to all rules of a set, so a set with
+// exclusion rules can't be referenced next to other positive rules, use Schedule.Union to combine such sets.
+// Names start with a letter or underscore and can contain letters, digits, underscores, dashes and dots.
+// Errors are reported as *ParseError with the line number and the offset in the line.
+func ParseDefinitions(rdr io.Reader) (map[string][]Rule, error) {
+	_, sets, err := parseFile(rdr, true)
+	return sets, err
+}
+
+// parseFile parses a rules file with definitions of named rule sets and rules using them. It returns
+// rules outside of definitions and the named sets. If defsOnly is set, every line must be a definition.
+func parseFile(rdr io.Reader, defsOnly bool) ([]Rule, map[string][]Rule, error) {
+	lines, err := readLines(rdr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var items []exprItem        // parts of lines outside of definitions
+	var itemLines []logicalLine // lines of the parts
+	sets := map[string][]Rule{}
+	for _, line := range lines {
+		name, namePos, bodyPos, ok := splitDefinition(line.text)
+		if !ok {
+			if defsOnly {
+				text := strings.TrimSpace(line.text)
+				pe := &ParseError{Input: text, Offset: strings.Index(line.text, text),
+					Err: badFormat("definition expected, e.g. name = rules")}
+				line.locate(pe)
+				return nil, nil, pe
+			}
+			lineItems, err := parseItems(line.text, sets)
+			if err != nil {
+				return nil, nil, locateError(err, line, len(items), 0)
+			}
+			items = append(items, lineItems...)
+			for range lineItems {
+				itemLines = append(itemLines, line)
+			}
+			continue
+		}
+
+		if _, ok := sets[name]; ok {
+			pe := newParseError(strings.TrimSpace(line.text), "definition", token{text: name, pos: namePos},
+				badFormat("rule set %q is already defined", name))
+			line.locate(pe)
+			return nil, nil, pe
+		}
+		rules, err := parseExpr(line.text[bodyPos:], sets)
+		if err != nil {
+			return nil, nil, locateError(err, line, 0, bodyPos)
+		}
+		sets[name] = rules
+	}
+
+	// all rules outside of definitions make a single set, so references are checked across lines
+	if err := checkScopes(items); err != nil {
+		return nil, nil, locateError(err, itemLines[err.Rule], 0, 0)
+	}
+	return joinItems(items), sets, nil
+}
+
+// locateError moves the ParseError of the line by the given number of rules and bytes,
+// and sets its line number and offset in the line
+func locateError(err error, line logicalLine, rules, offset int) error {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		pe.Rule += rules
+		pe.Offset += offset
+		line.locate(pe)
+	}
+	return err
+}
+
+// splitDefinition checks if the line is a definition of a named rule set, "name = rules", and returns
+// the name, its offset and the offset of the rules
+func splitDefinition(line string) (name string, namePos, bodyPos int, ok bool) {
+	key, _, found := strings.Cut(line, "=")
+	name = strings.TrimSpace(key)
+	if !found || !isSetName(name) {
+		return "", 0, 0, false
+	}
+	for _, opt := range optionKeys {
+		if name == opt {
+			return "", 0, 0, false // rule option, like TZ=UTC
+		}
+	}
+	return name, strings.Index(line, name), len(key) + 1, true
+}
+
+// isSetName checks if s is a valid name of a rule set
+func isSetName(s string) bool {
+	for i, c := range s {
+		switch {
+		case unicode.IsLetter(c) || c == '_':
+		case i > 0 && (unicode.IsDigit(c) || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
+
+// resolveReference returns the rules of a reference to a named rule set, @name, or the rules of the set
+// as exclusion rules for !@name
+func resolveReference(ref string, sets map[string][]Rule) ([]Rule, error) {
+	name, exclude := strings.CutPrefix(ref, "!")
+	name = strings.TrimPrefix(name, "@")
+	tok := token{text: ref}
+	if sets == nil {
+		return nil, newParseError(ref, "reference", tok, badFormat("references are supported in rule files only"))
+	}
+
+	rules, ok := sets[name]
+	if !ok {
+		return nil, newParseError(ref, "reference", tok,
+			badFormat("unknown rule set %q, sets must be defined before use", name))
+	}
+	if !exclude {
+		return rules, nil
+	}
+
+	res := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		if r.exclude {
+			return nil, newParseError(ref, "reference", tok,
+				badFormat("rule set %q with exclusion rules can't be excluded", name))
+		}
+		r.exclude = true
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// checkScopes checks that exclusion rules of referenced sets don't apply to other rules. Exclusion rules
+// apply to all rules of a set, so a reference @name to a set with exclusion rules can't be combined with
+// other positive rules without changing the meaning of the referenced set. The error is reported
+// for the reference, with the index of the part in Rule.
+func checkScopes(items []exprItem) *ParseError {
+	positive := func(r Rule) bool { return !r.exclude }
+	for i, item := range items {
+		if !item.ref || !slices.ContainsFunc(item.rules, func(r Rule) bool { return r.exclude }) {
+			continue
+		}
+		for j, other := range items {
+			if j == i || !slices.ContainsFunc(other.rules, positive) {
+				continue
+			}
+			pe := newParseError(item.text, "reference", token{text: item.text, pos: item.pos},
+				badFormat("rule set %q with exclusion rules can't be combined with other rules, "+
+					"its exclusions would apply to them too", strings.TrimPrefix(item.text, "@")))
+			pe.Rule = i
+			return pe
+		}
+	}
+	return nil
+}
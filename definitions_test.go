@@ -0,0 +1,153 @@
+package cronrange
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseDefinitions(t *testing.T) {
+	input := `# shared windows
+business = 09:00-17:00 1-5 * *
+weekend  = * 0,6 * *   # all day
+holidays = * * 25 dec; * * 1 jan
+support  = @business; 10:00-14:00 6 * *; !@holidays
+# exclusions apply to all rules of a set, so a set with exclusions, like support, can be narrowed
+# with more exclusions, but not combined with other rules, see TestDefinitionErrors
+support.no-lunch = @support; !12:00-13:00 * * *
+extended = \
+    @business; \
+    18:00-20:00 1-5 * *
+night.shift = 22:00-06:00 * * *
+`
+	sets, err := ParseDefinitions(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"business":         "09:00-17:00 1-5 * *",
+		"weekend":          "* 0,6 * *",
+		"holidays":         "* * 25 12; * * 1 1",
+		"support":          "09:00-17:00 1-5 * *; 10:00-14:00 6 * *; !* * 25 12; !* * 1 1",
+		"support.no-lunch": "09:00-17:00 1-5 * *; 10:00-14:00 6 * *; !* * 25 12; !* * 1 1; !12:00-13:00 * * *",
+		"extended":         "09:00-17:00 1-5 * *; 18:00-20:00 1-5 * *",
+		"night.shift":      "22:00-06:00 * * *",
+	}
+	if len(sets) != len(want) {
+		t.Errorf("ParseDefinitions() returned %d sets, want %d", len(sets), len(want))
+	}
+	for name, w := range want {
+		if got := Rules(sets[name]).String(); got != w {
+			t.Errorf("set %q = %q, want %q", name, got, w)
+		}
+	}
+}
+
+func TestParseFromReaderWithDefinitions(t *testing.T) {
+	input := `business = 09:00-17:00 1-5 * *
+lunch = 12:00-13:00 * * *
+TZ=UTC 08:00-09:00 1 * *
+@business; !@lunch
+`
+	rules, err := ParseFromReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := Rules(rules).String(), "TZ=UTC 08:00-09:00 1 * *; 09:00-17:00 1-5 * *; !12:00-13:00 * * *"; got != want {
+		t.Errorf("ParseFromReader() = %q, want %q", got, want)
+	}
+}
+
+func TestDefinitionErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		defsOnly bool
+		field    string
+		line     int
+		offset   int
+		text     string
+	}{
+		{
+			name:  "reference before definition",
+			input: "support = @business\nbusiness = 09:00-17:00 1-5 * *",
+			field: "reference", line: 1, offset: 10, text: "@business",
+		},
+		{
+			name:  "unknown reference in rules",
+			input: "a = * * * *\n* 0 * *; @b",
+			field: "reference", line: 2, offset: 9, text: "@b",
+		},
+		{
+			name:  "redefinition",
+			input: "a = * * * *\n  a = * 0 * *",
+			field: "definition", line: 2, offset: 2, text: "a",
+		},
+		{
+			name:  "invalid rule in definition",
+			input: "a = * * * *\nb = @a;  * 9 * *",
+			field: "dow", line: 2, offset: 11, text: "9",
+		},
+		{
+			name:  "invalid rule in continued definition",
+			input: "a = \\\n  * * * *; \\\n  * * 32 *",
+			field: "dom", line: 3, offset: 6, text: "32",
+		},
+		{
+			name:  "excluding set with exclusions",
+			input: "a = * * * *; !* 0 * *\nb = !@a",
+			field: "reference", line: 2, offset: 4, text: "!@a",
+		},
+		{
+			// the lunch break of business would apply to the weekend too
+			name:  "combining set with exclusions",
+			input: "business = 09:00-17:00 1-5 * *; !12:00-13:00 * * *\nsupport = @business; * 0,6 * *",
+			field: "reference", line: 2, offset: 10, text: "@business",
+		},
+		{
+			name:  "combining set with exclusions across lines",
+			input: "a = * 1 * *; !12:00-13:00 * * *\n* 0 * *\n  @a",
+			field: "reference", line: 3, offset: 2, text: "@a",
+		},
+		{
+			name:  "combining set of exclusions",
+			input: "a = !* 0 * *\n* * * *; @a",
+			field: "reference", line: 2, offset: 9, text: "@a",
+		},
+		{
+			name:     "rule in definitions file",
+			input:    "a = * * * *\n  * 0 * *",
+			defsOnly: true,
+			line:     2, offset: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.defsOnly {
+				_, err = ParseDefinitions(strings.NewReader(tt.input))
+			} else {
+				_, err = ParseFromReader(strings.NewReader(tt.input))
+			}
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("error = %v, want *ParseError", err)
+			}
+			if pe.Field != tt.field || pe.Line != tt.line || pe.Offset != tt.offset || pe.Text != tt.text {
+				t.Errorf("error = {Field: %q, Line: %d, Offset: %d, Text: %q}, want {%q, %d, %d, %q}",
+					pe.Field, pe.Line, pe.Offset, pe.Text, tt.field, tt.line, tt.offset, tt.text)
+			}
+			if !errors.Is(err, ErrBadFormat) && !errors.Is(err, ErrOutOfRange) {
+				t.Errorf("error = %v, want sentinel cause", err)
+			}
+		})
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	if _, err := Parse("@business"); !errors.Is(err, ErrBadFormat) {
+		t.Errorf("Parse() error = %v, want ErrBadFormat", err)
+	}
+}
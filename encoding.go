@@ -0,0 +1,222 @@
+package cronrange
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Rules is a set of rules which can be used directly in JSON and YAML configs. It is encoded as a single
+// expression string, e.g. "09:00-17:00 1-5 * *; * 0,6 * *", and decoded from such a string, a list of
+// expressions or a list of RuleSpec objects, which can be mixed.
+type Rules []Rule
+
+// RuleSpec is a structured form of a rule for configs and JSON schemas, with every part of the rule in its
+// own field. Fields use the same syntax as the parts of an expression, empty fields of the rule mean
+// no restriction and empty options mean their defaults.
+type RuleSpec struct {
+	Exclude  bool   `json:"exclude,omitempty" yaml:"exclude,omitempty"`   // exclusion rule, the time must not match it
+	TZ       string `json:"tz,omitempty" yaml:"tz,omitempty"`             // IANA timezone, e.g. Europe/Berlin
+	DST      string `json:"dst,omitempty" yaml:"dst,omitempty"`           // wall or elapsed
+	Days     string `json:"days,omitempty" yaml:"days,omitempty"`         // and or or
+	Calendar string `json:"calendar,omitempty" yaml:"calendar,omitempty"` // name of a registered calendar
+	Time     string `json:"time,omitempty" yaml:"time,omitempty"`         // e.g. 09:00-17:00
+	Dow      string `json:"dow,omitempty" yaml:"dow,omitempty"`           // e.g. 1-5 or mon-fri
+	Dom      string `json:"dom,omitempty" yaml:"dom,omitempty"`           // e.g. 1,15 or L
+	Month    string `json:"month,omitempty" yaml:"month,omitempty"`       // e.g. 4-9 or apr-sep
+	Dates    string `json:"dates,omitempty" yaml:"dates,omitempty"`       // e.g. 2026 or 2026-12-20..2027-01-03
+}
+
+// MarshalText implements encoding.TextMarshaler, the text is the same as String returns
+func (r Rule) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, the text must be a single rule
+func (r *Rule) UnmarshalText(text []byte) error {
+	expr := strings.TrimSpace(string(text))
+	if strings.Contains(expr, ";") {
+		return fmt.Errorf("invalid rule %q: single rule expected, use Rules for multiple rules", expr)
+	}
+	rule, err := parseRule(expr)
+	if err != nil {
+		return err
+	}
+	*r = rule
+	return nil
+}
+
+// Spec returns the structured form of the rule
+func (r Rule) Spec() RuleSpec {
+	res := RuleSpec{
+		Exclude:  r.exclude,
+		Calendar: r.calName,
+		Time:     r.timeRange.String(),
+		Dow:      r.dow.String(),
+		Dom:      r.dom.String(),
+		Month:    r.month.String(),
+	}
+	if r.loc != nil {
+		res.TZ = r.loc.String()
+	}
+	if r.dst != DSTWall {
+		res.DST = r.dst.String()
+	}
+	if r.daysOr {
+		res.Days = "or"
+	}
+	if len(r.dates) > 0 {
+		res.Dates = formatDates(r.dates)
+	}
+	return res
+}
+
+// Rule makes a rule from its structured form
+func (s RuleSpec) Rule() (Rule, error) {
+	var opts []string
+	options := []struct{ key, val string }{{"TZ", s.TZ}, {"DST", s.DST}, {"DAYS", s.Days}, {"CAL", s.Calendar}}
+	for _, opt := range options {
+		if opt.val != "" {
+			opts = append(opts, opt.key+"="+opt.val)
+		}
+	}
+
+	fields := []string{s.Time, s.Dow, s.Dom, s.Month, s.Dates}
+	for i, f := range fields {
+		if f == "" {
+			fields[i] = "*"
+		}
+	}
+
+	parts := append(opts, fields...)
+	for _, p := range parts {
+		if len(strings.Fields(p)) != 1 || strings.Contains(p, ";") {
+			return Rule{}, fmt.Errorf("invalid rule spec: %q is not a single value", p)
+		}
+	}
+
+	expr := strings.Join(parts, " ")
+	if s.Exclude {
+		expr = "!" + expr
+	}
+	rule, err := parseRule(expr)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid rule spec: %w", err)
+	}
+	return rule, nil
+}
+
+// String returns the rules as a single expression with rules separated by semicolons
+func (rs Rules) String() string {
+	res := make([]string, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, r.String())
+	}
+	return strings.Join(res, "; ")
+}
+
+// MarshalText implements encoding.TextMarshaler, the text is the same as String returns
+func (rs Rules) MarshalText() ([]byte, error) {
+	return []byte(rs.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the text as an expression.
+// An empty text makes an empty set of rules.
+func (rs *Rules) UnmarshalText(text []byte) error {
+	expr := strings.TrimSpace(string(text))
+	if expr == "" {
+		*rs = Rules{}
+		return nil
+	}
+	rules, err := Parse(expr)
+	if err != nil {
+		return err
+	}
+	*rs = rules
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding an expression string or a list of expressions
+// and RuleSpec objects
+func (rs *Rules) UnmarshalJSON(data []byte) error {
+	var expr string
+	if err := json.Unmarshal(data, &expr); err == nil {
+		return rs.UnmarshalText([]byte(expr))
+	}
+
+	var entries []ruleEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	*rs = joinEntries(entries)
+	return nil
+}
+
+// UnmarshalYAML decodes an expression string or a list of expressions and RuleSpec objects.
+// It implements the unmarshaler interface supported by both gopkg.in/yaml.v2 and gopkg.in/yaml.v3.
+func (rs *Rules) UnmarshalYAML(unmarshal func(any) error) error {
+	var expr string
+	if err := unmarshal(&expr); err == nil {
+		return rs.UnmarshalText([]byte(expr))
+	}
+
+	var entries []ruleEntry
+	if err := unmarshal(&entries); err != nil {
+		return err
+	}
+	*rs = joinEntries(entries)
+	return nil
+}
+
+// ruleEntry is an item of a rule list in JSON or YAML, either an expression or a RuleSpec object
+type ruleEntry struct {
+	rules []Rule
+}
+
+// UnmarshalJSON implements json.Unmarshaler, rejecting unknown keys of RuleSpec objects
+func (e *ruleEntry) UnmarshalJSON(data []byte) error {
+	return e.decode(func(v any) error {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		return dec.Decode(v)
+	})
+}
+
+// UnmarshalYAML decodes the entry with the unmarshal function of a YAML decoder
+func (e *ruleEntry) UnmarshalYAML(unmarshal func(any) error) error {
+	return e.decode(unmarshal)
+}
+
+// decode tries to decode the entry as an expression first and as a RuleSpec object next
+func (e *ruleEntry) decode(unmarshal func(any) error) error {
+	var expr string
+	if err := unmarshal(&expr); err == nil {
+		rules, err := Parse(expr)
+		if err != nil {
+			return err
+		}
+		e.rules = rules
+		return nil
+	}
+
+	var spec RuleSpec
+	if err := unmarshal(&spec); err != nil {
+		return fmt.Errorf("rule must be a string or an object: %w", err)
+	}
+	rule, err := spec.Rule()
+	if err != nil {
+		return err
+	}
+	e.rules = []Rule{rule}
+	return nil
+}
+
+// joinEntries returns the rules of all entries in order
+func joinEntries(entries []ruleEntry) Rules {
+	res := Rules{}
+	for _, e := range entries {
+		res = append(res, e.rules...)
+	}
+	return res
+}
@@ -0,0 +1,199 @@
+package cronrange
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRuleText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "simple", text: "09:00-17:00 1-5 * *", want: "09:00-17:00 1-5 * *"},
+		{name: "names and options", text: " !TZ=UTC 22:00-02:00) mon-fri L jan 2026 ", want: "!TZ=UTC 22:00-02:00) 1-5 L 1 2026"},
+		{name: "multiple rules", text: "* * * *; * 0 * *", wantErr: true},
+		{name: "invalid", text: "25:00-26:00 * * *", wantErr: true},
+		{name: "empty", text: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Rule
+			err := r.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			text, err := r.MarshalText()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(text) != tt.want {
+				t.Errorf("MarshalText() = %q, want %q", text, tt.want)
+			}
+		})
+	}
+}
+
+func TestRulesJSON(t *testing.T) {
+	type config struct {
+		Active Rules `json:"active"`
+		Single *Rule `json:"single,omitempty"`
+	}
+
+	tests := []struct {
+		name    string
+		json    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "expression",
+			json: `{"active": "09:00-17:00 1-5 * *; * 0,6 * *"}`,
+			want: `{"active":"09:00-17:00 1-5 * *; * 0,6 * *"}`,
+		},
+		{
+			name: "empty expression",
+			json: `{"active": ""}`,
+			want: `{"active":""}`,
+		},
+		{
+			name: "list of expressions and specs",
+			json: `{"active": ["09:00-17:00 1-5 * *; !12:00-13:00 * * *",
+				{"time": "10:00-14:00", "dow": "sat", "tz": "UTC", "dates": "2026"},
+				{"exclude": true, "dom": "25", "month": "dec"}]}`,
+			want: `{"active":"09:00-17:00 1-5 * *; !12:00-13:00 * * *; TZ=UTC 10:00-14:00 6 * * 2026; !* * 25 12"}`,
+		},
+		{
+			name: "single rule",
+			json: `{"active": "* * * *", "single": "08:00-09:00 * * *"}`,
+			want: `{"active":"* * * *","single":"08:00-09:00 * * *"}`,
+		},
+		{name: "invalid expression", json: `{"active": "25:00-26:00 * * *"}`, wantErr: true},
+		{name: "invalid spec", json: `{"active": [{"time": "25:00-26:00"}]}`, wantErr: true},
+		{name: "unknown spec key", json: `{"active": [{"tme": "09:00-10:00"}]}`, wantErr: true},
+		{name: "spec value with spaces", json: `{"active": [{"dow": "1 2"}]}`, wantErr: true},
+		{name: "wrong type", json: `{"active": 42}`, wantErr: true},
+		{name: "multiple rules in single", json: `{"single": "* * * *; * 0 * *"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg config
+			err := json.Unmarshal([]byte(tt.json), &cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("json.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			data, err := json.Marshal(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", data, tt.want)
+			}
+
+			// the encoded form decodes to the same rules
+			var back config
+			if err := json.Unmarshal(data, &back); err != nil {
+				t.Fatal(err)
+			}
+			if back.Active.String() != cfg.Active.String() {
+				t.Errorf("round trip = %q, want %q", back.Active, cfg.Active)
+			}
+		})
+	}
+}
+
+func TestRulesYAML(t *testing.T) {
+	// the unmarshal function of a YAML decoder is simulated with JSON of the same structure, calling
+	// UnmarshalYAML of list items the way a YAML decoder does
+	var yamlFunc func(data string) func(any) error
+	yamlFunc = func(data string) func(any) error {
+		return func(v any) error {
+			entries, ok := v.(*[]ruleEntry)
+			if !ok {
+				return json.Unmarshal([]byte(data), v)
+			}
+			var items []json.RawMessage
+			if err := json.Unmarshal([]byte(data), &items); err != nil {
+				return err
+			}
+			for _, item := range items {
+				var e ruleEntry
+				if err := e.UnmarshalYAML(yamlFunc(string(item))); err != nil {
+					return err
+				}
+				*entries = append(*entries, e)
+			}
+			return nil
+		}
+	}
+
+	var rs Rules
+	if err := rs.UnmarshalYAML(yamlFunc(`"* 0,6 * *"`)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rs.String(), "* 0,6 * *"; got != want {
+		t.Errorf("UnmarshalYAML() = %q, want %q", got, want)
+	}
+
+	if err := rs.UnmarshalYAML(yamlFunc(`["* 0 * *", {"time": "09:00-17:00", "dow": "mon-fri"}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rs.String(), "* 0 * *; 09:00-17:00 1-5 * *"; got != want {
+		t.Errorf("UnmarshalYAML() = %q, want %q", got, want)
+	}
+
+	if err := rs.UnmarshalYAML(yamlFunc(`[{"time": "9-17"}]`)); err == nil {
+		t.Error("UnmarshalYAML() expected error for invalid spec")
+	}
+	if err := rs.UnmarshalYAML(yamlFunc(`["* 0 * *", "* 9 * *"]`)); err == nil {
+		t.Error("UnmarshalYAML() expected error for invalid expression")
+	}
+	if err := rs.UnmarshalYAML(yamlFunc(`[42]`)); err == nil {
+		t.Error("UnmarshalYAML() expected error for a number")
+	}
+}
+
+func TestRuleSpec(t *testing.T) {
+	RegisterCalendar("test-spec", NewHolidays())
+	exprs := []string{
+		"* * * *",
+		"09:00-17:00) 1-5,5L * 4-9",
+		"!TZ=Europe/Berlin DST=elapsed DAYS=or CAL=test-spec 22:00-02:00 1 H,L */3 2026-12-24,2027",
+	}
+	for _, expr := range exprs {
+		rules, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", expr, err)
+		}
+		spec := rules[0].Spec()
+		rule, err := spec.Rule()
+		if err != nil {
+			t.Fatalf("Rule() of %+v error = %v", spec, err)
+		}
+		if rule.String() != rules[0].String() {
+			t.Errorf("Rule() = %q, want %q", rule, rules[0])
+		}
+	}
+
+	rule, err := RuleSpec{Time: "09:00-10:00", Dow: "mon"}.Rule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Match([]Rule{rule}, time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("rule %q doesn't match Monday 9:30", rule)
+	}
+	if _, err := (RuleSpec{DST: "never"}).Rule(); err == nil {
+		t.Error("Rule() expected error for invalid DST policy")
+	}
+}
@@ -0,0 +1,104 @@
+package cronrange
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrBadFormat is the cause of parse errors for malformed input, like "9-17" in the time field
+	// or an unknown option
+	ErrBadFormat = errors.New("bad format")
+	// ErrOutOfRange is the cause of parse errors for well-formed values outside of their valid range,
+	// like 25:00 or day of week 7
+	ErrOutOfRange = errors.New("out of range")
+)
+
+// ParseError describes an invalid part of a cronrange expression. Its cause wraps ErrBadFormat
+// or ErrOutOfRange, so errors.Is can tell them apart.
+// Rule counts semicolon-separated rules as written, so a reference to a named rule set is a single rule.
+// In rule files it is the index among all rules outside of definitions, or within the invalid definition.
+type ParseError struct {
+	Rule   int    // index of the invalid rule in the expression, starting from 0
+	Input  string // the invalid rule
+	Field  string // invalid part: option, time, dow, dom, month, dates, reference or definition, empty for the whole rule
+	Line   int    // line number of Text for rules read by ParseFromReader, starting from 1, zero otherwise
+	Offset int    // byte offset of Text in the expression or in the line, starting from 0
+	Text   string // the invalid text, e.g. a single value of a list
+	Err    error  // the cause
+}
+
+// Error returns the description of the error
+func (e *ParseError) Error() string {
+	var prefix string
+	if e.Line > 0 {
+		prefix = fmt.Sprintf("line %d: ", e.Line)
+	}
+	if e.Field == "" {
+		return fmt.Sprintf("%sinvalid rule %q: %v", prefix, e.Input, e.Err)
+	}
+	return fmt.Sprintf("%sinvalid rule %q: invalid %s %q: %v", prefix, e.Input, e.Field, e.Text, e.Err)
+}
+
+// Unwrap returns the cause of the error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// causeError is an error message with a sentinel cause, which is matched by errors.Is but not repeated
+// in the message
+type causeError struct {
+	msg   string
+	cause error
+}
+
+func (e *causeError) Error() string { return e.msg }
+func (e *causeError) Unwrap() error { return e.cause }
+
+// badFormat returns an error with ErrBadFormat cause
+func badFormat(format string, args ...any) error {
+	return &causeError{msg: fmt.Sprintf(format, args...), cause: ErrBadFormat}
+}
+
+// outOfRange returns an error with ErrOutOfRange cause
+func outOfRange(format string, args ...any) error {
+	return &causeError{msg: fmt.Sprintf(format, args...), cause: ErrOutOfRange}
+}
+
+// partError is an error in a part of a field, like a single value of a list, at byte offset pos
+// within the field
+type partError struct {
+	pos  int
+	text string
+	err  error
+}
+
+func (e *partError) Error() string { return e.err.Error() }
+func (e *partError) Unwrap() error { return e.err }
+
+// atPart marks the error as related to the part of a field at byte offset pos, keeping the innermost part
+// if the error is already marked
+func atPart(pos int, text string, err error) error {
+	var pe *partError
+	if errors.As(err, &pe) {
+		return &partError{pos: pos + pe.pos, text: pe.text, err: pe.err}
+	}
+	return &partError{pos: pos, text: text, err: err}
+}
+
+// token is a whitespace-separated part of a rule with its byte offset in the rule
+type token struct {
+	text string
+	pos  int
+}
+
+// newParseError makes a ParseError for the rule with the error in the token of the given field,
+// pointing to the part of the token if the error is marked with atPart
+func newParseError(rule, field string, tok token, err error) *ParseError {
+	res := &ParseError{Input: rule, Field: field, Offset: tok.pos, Text: tok.text, Err: err}
+	var pe *partError
+	if errors.As(err, &pe) {
+		res.Offset, res.Text, res.Err = tok.pos+pe.pos, pe.text, pe.err
+	}
+	return res
+}
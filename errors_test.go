@@ -0,0 +1,148 @@
+package cronrange
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name   string
+		expr   string
+		rule   int
+		field  string
+		offset int
+		text   string
+		cause  error
+	}{
+		{name: "hour out of range", expr: "25:00-26:00 1-5 * *", field: "time", offset: 0, text: "25:00", cause: ErrOutOfRange},
+		{name: "bad end time", expr: "09:00-5pm 1-5 * *", field: "time", offset: 6, text: "5pm", cause: ErrBadFormat},
+		{name: "bad time range", expr: "9to17 1-5 * *", field: "time", offset: 0, text: "9to17", cause: ErrBadFormat},
+		{name: "dow value in list", expr: "* 1,3,9 * *", field: "dow", offset: 6, text: "9", cause: ErrOutOfRange},
+		{name: "dow name", expr: "* mon-fry * *", field: "dow", offset: 2, text: "mon-fry", cause: ErrBadFormat},
+		{name: "dow spec", expr: "* 1,5#6 * *", field: "dow", offset: 4, text: "5#6", cause: ErrOutOfRange},
+		{name: "dom after spec", expr: "* * L,15W,32 *", field: "dom", offset: 10, text: "32", cause: ErrOutOfRange},
+		{name: "dom spec", expr: "* * 1,L-x *", field: "dom", offset: 6, text: "L-x", cause: ErrBadFormat},
+		{name: "holiday without calendar", expr: "* * H *", field: "dom", offset: 4, text: "H", cause: ErrBadFormat},
+		{name: "month step", expr: "* * * */0", field: "month", offset: 6, text: "*/0", cause: ErrOutOfRange},
+		{name: "month name", expr: "* * * jan,fbe", field: "month", offset: 10, text: "fbe", cause: ErrBadFormat},
+		{name: "dates", expr: "* * * * 2026,2027-13-01", field: "dates", offset: 13, text: "2027-13-01", cause: ErrBadFormat},
+		{name: "reversed years", expr: "* * * * 2027-2026", field: "dates", offset: 8, text: "2027-2026", cause: ErrOutOfRange},
+		{name: "option", expr: "!TZ=UTC DST=never * * * *", field: "option", offset: 8, text: "DST=never", cause: ErrBadFormat},
+		{name: "unknown timezone", expr: "TZ=Mars/Olympus * * * *", field: "option", offset: 0, text: "TZ=Mars/Olympus", cause: ErrBadFormat},
+		{name: "fields count", expr: "* * *", cause: ErrBadFormat},
+		{name: "second rule", expr: "* * * *;  09:00-17:00 8 * *", rule: 1, field: "dow", offset: 22, text: "8", cause: ErrOutOfRange},
+		{name: "third rule", expr: "* * * *; * 0 * *; * * * 1,2,13", rule: 2, field: "month", offset: 28, text: "13", cause: ErrOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("Parse() error = %v, want *ParseError", err)
+			}
+			if pe.Rule != tt.rule || pe.Field != tt.field || pe.Offset != tt.offset || pe.Text != tt.text {
+				t.Errorf("Parse() error = {Rule: %d, Field: %q, Offset: %d, Text: %q}, want {%d, %q, %d, %q}",
+					pe.Rule, pe.Field, pe.Offset, pe.Text, tt.rule, tt.field, tt.offset, tt.text)
+			}
+			if tt.text != "" && !strings.HasPrefix(tt.expr[pe.Offset:], tt.text) {
+				t.Errorf("offset %d points to %q, want %q", pe.Offset, tt.expr[pe.Offset:], tt.text)
+			}
+			if !errors.Is(err, tt.cause) {
+				t.Errorf("Parse() error = %v, want cause %v", err, tt.cause)
+			}
+			other := ErrBadFormat
+			if tt.cause == ErrBadFormat {
+				other = ErrOutOfRange
+			}
+			if errors.Is(err, other) {
+				t.Errorf("Parse() error = %v, unexpected cause %v", err, other)
+			}
+		})
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	_, err := Parse("* * * *; 09:00-17:00 1-5 32 *")
+	want := `invalid rule "09:00-17:00 1-5 32 *": invalid dom "32": value out of range`
+	if err == nil || err.Error() != want {
+		t.Errorf("Parse() error = %v, want %s", err, want)
+	}
+
+	_, err = Parse("09:00-17:00 1-5 *")
+	want = `invalid rule "09:00-17:00 1-5 *": rule must have 4 or 5 fields: time dow dom month [dates]`
+	if err == nil || err.Error() != want {
+		t.Errorf("Parse() error = %v, want %s", err, want)
+	}
+}
+
+func TestParseFromReaderError(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		rule   int
+		line   int
+		offset int
+		text   string
+	}{
+		{
+			name:  "third rule on third line",
+			input: "* * * *; * 0 * *\n\n09:00-17:00 1-5 * 0\n",
+			rule:  2, line: 3, offset: 18, text: "0",
+		},
+		{
+			name:  "after comments",
+			input: "# header\n* * * * # all\n\n  # weekdays\n  09:00-17:00 1-8 * *\n",
+			rule:  1, line: 5, offset: 14, text: "1-8",
+		},
+		{
+			name:  "continued line",
+			input: "TZ=UTC \\\n  09:00-17:00 \\\n  1-5 32 *\n",
+			rule:  0, line: 3, offset: 6, text: "32",
+		},
+		{
+			name:  "second rule of continued line",
+			input: "* * * *; \\\n  * 0 * *; * * * jan-dex\n",
+			rule:  2, line: 2, offset: 17, text: "jan-dex",
+		},
+		{
+			name:  "after reference",
+			input: "biz = * 1 * *; * 2 * *\n@biz; * * * *\n* 9 * *\n",
+			rule:  2, line: 3, offset: 2, text: "9",
+		},
+		{
+			name:  "in definition after reference",
+			input: "biz = * 1 * *; * 2 * *\nx = @biz; bad * * *\n",
+			rule:  1, line: 2, offset: 10, text: "bad",
+		},
+		{
+			name:  "reference to set with exclusions",
+			input: "biz = * 1 * *; !12:00-13:00 * * *\n* 0 * *; @biz\n",
+			rule:  1, line: 2, offset: 9, text: "@biz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFromReader(strings.NewReader(tt.input))
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("ParseFromReader() error = %v, want *ParseError", err)
+			}
+			if pe.Rule != tt.rule || pe.Line != tt.line || pe.Offset != tt.offset || pe.Text != tt.text {
+				t.Errorf("ParseFromReader() error = {Rule: %d, Line: %d, Offset: %d, Text: %q}, want {%d, %d, %d, %q}",
+					pe.Rule, pe.Line, pe.Offset, pe.Text, tt.rule, tt.line, tt.offset, tt.text)
+			}
+			line := strings.Split(tt.input, "\n")[pe.Line-1]
+			if !strings.HasPrefix(line[pe.Offset:], tt.text) {
+				t.Errorf("offset %d points to %q, want %q", pe.Offset, line[pe.Offset:], tt.text)
+			}
+			if want := fmt.Sprintf("line %d: ", tt.line); !strings.HasPrefix(err.Error(), want) {
+				t.Errorf("ParseFromReader() error = %v, want prefix %q", err, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package cronrange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// logicalLine is a line of a rules file with the comment removed and continuation lines joined
+type logicalLine struct {
+	text string
+	segs []lineSegment // physical lines the text is made of, in order
+}
+
+// lineSegment is a part of a logical line coming from a single physical line
+type lineSegment struct {
+	line  int // line number in the file, starting from 1
+	start int // byte offset of the segment in the logical line
+}
+
+// readLines reads logical lines from a rules file. A # starts a comment if it begins the line or follows
+// whitespace, so day specifiers like 5#3 are kept. A line ending with \ continues on the next line.
+// Lines without anything but whitespace and comments are skipped.
+func readLines(rdr io.Reader) ([]logicalLine, error) {
+	var res []logicalLine
+	var cur logicalLine
+	var text strings.Builder
+	flush := func() {
+		if strings.TrimSpace(text.String()) != "" {
+			cur.text = text.String()
+			res = append(res, cur)
+		}
+		cur = logicalLine{}
+		text.Reset()
+	}
+
+	scanner := bufio.NewScanner(rdr)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRightFunc(stripComment(scanner.Text()), unicode.IsSpace)
+		line, cont := strings.CutSuffix(line, `\`)
+		cur.segs = append(cur.segs, lineSegment{line: lineNum, start: text.Len()})
+		text.WriteString(line)
+		if cont {
+			text.WriteByte(' ')
+			continue
+		}
+		flush()
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading input: %w", err)
+	}
+	flush() // the last line may end with a continuation
+	return res, nil
+}
+
+// stripComment removes the comment from the line
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// locate converts the offset of the error in the logical line to the line number and the offset
+// in the physical line
+func (l logicalLine) locate(pe *ParseError) {
+	seg := l.segs[0]
+	for _, s := range l.segs[1:] {
+		if s.start > pe.Offset {
+			break
+		}
+		seg = s
+	}
+	pe.Line, pe.Offset = seg.line, pe.Offset-seg.start
+}
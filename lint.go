@@ -0,0 +1,170 @@
+package cronrange
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WarningKind is a kind of suspicious rule reported by Lint
+type WarningKind int
+
+const (
+	// WarnNeverMatches is reported for rules which can't match any time, e.g. "* * 31 2"
+	WarnNeverMatches WarningKind = iota
+	// WarnZeroLength is reported for time ranges with the same start and end, e.g. 10:00-10:00,
+	// which match a single second, or nothing if half-open
+	WarnZeroLength
+	// WarnDuplicate is reported for rules matching the same times as an earlier rule written the same way
+	WarnDuplicate
+	// WarnOvernightDow is reported for overnight ranges continuing into days not matched by dow,
+	// e.g. 22:00-06:00 1-5 is active on Saturday morning
+	WarnOvernightDow
+	// WarnRedundant is reported for rules matching only times matched by another rule of the set
+	WarnRedundant
+)
+
+// String returns the name of the warning kind
+func (k WarningKind) String() string {
+	switch k {
+	case WarnNeverMatches:
+		return "never-matches"
+	case WarnZeroLength:
+		return "zero-length"
+	case WarnDuplicate:
+		return "duplicate"
+	case WarnOvernightDow:
+		return "overnight-dow"
+	case WarnRedundant:
+		return "redundant"
+	}
+	return fmt.Sprintf("WarningKind(%d)", int(k))
+}
+
+// Warning describes a suspicious rule found by Lint
+type Warning struct {
+	Rule    int         // index of the rule in the set, starting from 0
+	Input   string      // the rule
+	Kind    WarningKind // kind of the problem
+	Message string      // description of the problem
+}
+
+// String returns the description of the warning
+func (w Warning) String() string {
+	return fmt.Sprintf("rule %d %q: %s", w.Rule, w.Input, w.Message)
+}
+
+// Lint checks rules for likely mistakes which parse fine, like rules never matching any time, e.g. "* * 31 2",
+// zero-length time ranges, duplicated and redundant rules, and overnight ranges continuing into days
+// not matched by dow. Warnings are ordered by rule. Whether a rule can match is checked within
+// the 400-year Gregorian cycle starting in 2000, extended to all absolute dates of the rule.
+func Lint(rules []Rule) []Warning {
+	var res []Warning
+	warn := func(i int, kind WarningKind, format string, args ...any) {
+		res = append(res, Warning{Rule: i, Input: rules[i].String(), Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+
+	dead := make([]bool, len(rules))
+	seen := map[string]int{}
+	for i, r := range rules {
+		if r.timeRange.start == r.timeRange.end && !r.timeRange.all {
+			dead[i] = r.timeRange.halfOpen
+			if dead[i] {
+				warn(i, WarnZeroLength, "time range %s is empty", r.timeRange)
+			} else {
+				warn(i, WarnZeroLength, "time range %s matches a single second", r.timeRange)
+			}
+		}
+		if !dead[i] && neverMatches(r) {
+			dead[i] = true
+			warn(i, WarnNeverMatches, "never matches%s", neverMatchesReason(r))
+		}
+		if days := spillDays(r); len(days) > 0 {
+			warn(i, WarnOvernightDow, "overnight range %s belongs to the day it starts on and continues into %s, "+
+				"not matched by dow", r.timeRange, strings.Join(days, ","))
+		}
+
+		key := canonicalRule(r).String()
+		if j, ok := seen[key]; ok {
+			dead[i] = true // no need to report the duplicate as redundant too
+			warn(i, WarnDuplicate, "duplicates rule %d %q", j, rules[j].String())
+			continue
+		}
+		seen[key] = i
+	}
+
+	for i, r := range rules {
+		if dead[i] {
+			continue
+		}
+		for j, other := range rules {
+			if i == j || dead[j] || !covers(canonicalRule(other), canonicalRule(r)) {
+				continue
+			}
+			if j > i && covers(canonicalRule(r), canonicalRule(other)) {
+				continue // rules covering each other are equivalent, the later one is reported
+			}
+			warn(i, WarnRedundant, "covered by rule %d %q", j, other.String())
+			break
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Rule < res[j].Rule })
+	return res
+}
+
+// neverMatches checks if the rule doesn't match any time within the horizon of comparisons,
+// regardless of the exclusion flag
+func neverMatches(r Rule) bool {
+	from, to := compareHorizon(time.UTC, []Rule{r})
+	for range unionSpans([]Rule{r}, from, to) {
+		return false
+	}
+	return true
+}
+
+// neverMatchesReason explains why a rule never matches if all its days of month don't exist
+// in its months, e.g. ": day 31 doesn't occur in month 2,4,6"
+func neverMatchesReason(r Rule) string {
+	if r.dom.all || len(r.dom.specs) > 0 || effectiveOr(r) {
+		return ""
+	}
+	for m := time.January; m <= time.December; m++ {
+		if !r.month.matches(int(m)) {
+			continue
+		}
+		for d := 1; d <= daysIn(2000, m); d++ { // 2000 is a leap year, so February has 29 days
+			if r.dom.matches(d) {
+				return ""
+			}
+		}
+	}
+	return fmt.Sprintf(": day %s doesn't occur in month %s", r.dom, r.month)
+}
+
+// spillDays returns names of days an overnight range of the rule continues into while dow doesn't match them
+func spillDays(r Rule) []string {
+	tr := r.timeRange
+	if !tr.overnight || (tr.halfOpen && tr.end == 0) || r.dow.all || effectiveOr(r) {
+		return nil
+	}
+
+	start := make([]bool, 7) // days of week windows can start on
+	for d := 0; d < 7; d++ {
+		start[d] = r.dow.matches(d)
+	}
+	for _, spec := range r.dow.specs {
+		if spec.kind == nthWeekday || spec.kind == lastWeekdayOfMonth {
+			start[spec.value] = true
+		}
+	}
+
+	var res []string
+	for d := 0; d < 7; d++ {
+		if next := (d + 1) % 7; start[d] && !start[next] {
+			res = append(res, dowNames[next])
+		}
+	}
+	return res
+}
@@ -0,0 +1,131 @@
+package cronrange
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	RegisterCalendar("test-lint-empty", NewHolidays())
+
+	tests := []struct {
+		name string
+		expr string
+		want []string
+	}{
+		{name: "clean", expr: "09:00-17:00 1-5 * *; 10:00-14:00 0,6 * *"},
+		{
+			name: "day not in month",
+			expr: "* * 31 2",
+			want: []string{`rule 0 "* * 31 2": never matches: day 31 doesn't occur in month 2`},
+		},
+		{
+			name: "days not in months",
+			expr: "* * 30-31 2; * * 31 2,4,6",
+			want: []string{
+				`rule 0 "* * 30-31 2": never matches: day 30-31 doesn't occur in month 2`,
+				`rule 1 "* * 31 2,4,6": never matches: day 31 doesn't occur in month 2,4,6`,
+			},
+		},
+		{name: "leap day", expr: "* * 29 2"},
+		{name: "fifth monday of february", expr: "* 1#5 * 2"},
+		{name: "no such weekday", expr: "* 1 13 2 2026", want: []string{`rule 0 "* 1 13 2 2026": never matches`}},
+		{
+			name: "empty calendar",
+			expr: "CAL=test-lint-empty * * H *",
+			want: []string{`rule 0 "CAL=test-lint-empty * * H *": never matches`},
+		},
+		{
+			name: "never matching exclusion",
+			expr: "* * * *; !* * 30 2",
+			want: []string{`rule 1 "!* * 30 2": never matches: day 30 doesn't occur in month 2`},
+		},
+		{
+			name: "zero-length",
+			expr: "10:00-10:00 * * *; 11:00-11:00) * * *",
+			want: []string{
+				`rule 0 "10:00-10:00 * * *": time range 10:00-10:00 matches a single second`,
+				`rule 1 "11:00-11:00) * * *": time range 11:00-11:00) is empty`,
+			},
+		},
+		{
+			name: "duplicates",
+			expr: "09:00-17:00 1-5 * *; 09:00-17:00 mon-fri * *; * 0,6 * *; * 6,0 * *; * 0-6 * 1; * * * 1",
+			want: []string{
+				`rule 1 "09:00-17:00 1-5 * *": duplicates rule 0 "09:00-17:00 1-5 * *"`,
+				`rule 3 "* 0,6 * *": duplicates rule 2 "* 0,6 * *"`,
+				`rule 5 "* * * 1": duplicates rule 4 "* 0-6 * 1"`,
+			},
+		},
+		{
+			name: "overnight",
+			expr: "22:00-06:00 1-5 * *; 23:00-01:00 5#3 * *; 22:00-06:00 * * *; 22:00-00:00) 5 * *",
+			want: []string{
+				`rule 0 "22:00-06:00 1-5 * *": overnight range 22:00-06:00 belongs to the day it starts on and ` +
+					`continues into sat, not matched by dow`,
+				`rule 0 "22:00-06:00 1-5 * *": covered by rule 2 "22:00-06:00 * * *"`,
+				`rule 1 "23:00-01:00 5#3 * *": overnight range 23:00-01:00 belongs to the day it starts on and ` +
+					`continues into sat, not matched by dow`,
+				`rule 1 "23:00-01:00 5#3 * *": covered by rule 0 "22:00-06:00 1-5 * *"`,
+				`rule 3 "22:00-00:00) 5 * *": covered by rule 0 "22:00-06:00 1-5 * *"`,
+			},
+		},
+		{
+			name: "overnight on separate days",
+			expr: "22:00-02:00 1,3 * *",
+			want: []string{
+				`rule 0 "22:00-02:00 1,3 * *": overnight range 22:00-02:00 belongs to the day it starts on and ` +
+					`continues into tue,thu, not matched by dow`,
+			},
+		},
+		{
+			name: "redundant",
+			expr: "09:00-17:00 * * *; 10:00-11:00 1 * *; !12:00-13:00 * * *; !12:15-12:45 * * *",
+			want: []string{
+				`rule 1 "10:00-11:00 1 * *": covered by rule 0 "09:00-17:00 * * *"`,
+				`rule 3 "!12:15-12:45 * * *": covered by rule 2 "!12:00-13:00 * * *"`,
+			},
+		},
+		{
+			name: "equivalent rules",
+			expr: "* 1-5 * *; DAYS=or * 1-5 * *",
+			want: []string{`rule 1 "DAYS=or * 1-5 * *": duplicates rule 0 "* 1-5 * *"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := Lint(rules)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Lint() = %v, want %v", got, tt.want)
+			}
+			for i, w := range got {
+				if w.String() != tt.want[i] {
+					t.Errorf("Lint()[%d] = %s, want %s", i, w, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWarningKind(t *testing.T) {
+	rules, err := Parse("* * 31 2; 10:00-10:00 * * *; * 1 * *; * 1 * *; 22:00-02:00 5 * *; 10:00-11:00 1 * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, w := range Lint(rules) {
+		kinds = append(kinds, w.Kind.String())
+	}
+	want := "never-matches zero-length duplicate overnight-dow redundant"
+	if got := strings.Join(kinds, " "); got != want {
+		t.Errorf("kinds = %q, want %q", got, want)
+	}
+	if got := WarningKind(42).String(); got != "WarningKind(42)" {
+		t.Errorf("String() = %q, want WarningKind(42)", got)
+	}
+}
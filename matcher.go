@@ -0,0 +1,51 @@
+package cronrange
+
+import "time"
+
+// Matcher checks times against a fixed set of rules. It is meant for hot paths, like checking every
+// request against the same rules: Match doesn't allocate and reads the wall clock of the checked time
+// once for all rules without a timezone. A Matcher is safe for concurrent use.
+type Matcher struct {
+	include []Rule
+	exclude []Rule
+}
+
+// Compile makes a Matcher for the rules. The rules are copied, so later changes of the slice
+// don't affect the Matcher.
+func Compile(rules []Rule) *Matcher {
+	m := &Matcher{}
+	for _, r := range rules {
+		if r.exclude {
+			m.exclude = append(m.exclude, r)
+			continue
+		}
+		m.include = append(m.include, r)
+	}
+	return m
+}
+
+// Match checks if the given time matches any of the rules and none of the exclusion rules,
+// the same way as the package-level Match does
+func (m *Matcher) Match(t time.Time) bool {
+	days, secs := wallClock(t)
+	for _, r := range m.exclude {
+		if r.matchesAt(t, days, secs) {
+			return false
+		}
+	}
+	for _, r := range m.include {
+		if r.matchesAt(t, days, secs) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAt checks if the rule matches t, reusing the wall clock of t in its own location, given as days
+// since 1970-01-01 and seconds since midnight, for rules without a timezone and the wall DST policy
+func (r Rule) matchesAt(t time.Time, days, secs int64) bool {
+	if r.loc != nil || (r.dst == DSTElapsed && !r.timeRange.all) {
+		return r.matches(t)
+	}
+	return r.matchesWall(days, secs)
+}
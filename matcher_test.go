@@ -0,0 +1,79 @@
+package cronrange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatcher(t *testing.T) {
+	RegisterCalendar("test-matcher", NewHolidays(time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)))
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exprs := []string{
+		"09:00-17:00 1-5 * *",
+		"23:00-02:00 fri * *",
+		"DST=elapsed 01:30-02:30 * * *",
+		"* 0,6 * *; 12:00-13:00 * 1,15,L *",
+		"TZ=Asia/Kolkata 23:00-01:00 * * *; TZ=Europe/London 09:00-10:00) * * *",
+		"* * * *; !01:00-02:00 * * *; !CAL=test-matcher * * H *",
+		"DAYS=or 08:00-20:00 1#2,5L 15W * 2024",
+		"* */2 */3 mar-nov 2024-03-09..2024-03-12",
+	}
+	periods := []time.Time{
+		time.Date(2024, 3, 8, 0, 0, 0, 0, ny),
+		time.Date(2024, 11, 1, 0, 0, 0, 0, ny),
+		time.Date(2024, 6, 28, 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, expr := range exprs {
+		rules, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", expr, err)
+		}
+		m := Compile(rules)
+		for _, from := range periods {
+			to := from.Add(4 * 24 * time.Hour)
+			for tm := from; tm.Before(to); tm = tm.Add(7 * time.Minute) {
+				if got, want := m.Match(tm), Match(rules, tm); got != want {
+					t.Fatalf("%q at %v: Matcher.Match() = %v, Match() = %v", expr, tm, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestMatcherAllocs(t *testing.T) {
+	RegisterCalendar("test-matcher-allocs", NewHolidays(time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)))
+	rules, err := Parse("09:00-17:00 1-5 * *; 22:00-02:00 5L * *; TZ=Europe/Berlin DST=elapsed 02:00-03:00 * * *;" +
+		" !CAL=test-matcher-allocs * * H *; * * * * 2024-12-20..2025-01-03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Compile(rules)
+	tm := time.Date(2024, 3, 31, 1, 30, 0, 0, time.UTC)
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		m.Match(tm)
+		tm = tm.Add(17 * time.Minute)
+	})
+	if allocs != 0 {
+		t.Errorf("Matcher.Match() allocates %v times per run, want 0", allocs)
+	}
+}
+
+func BenchmarkMatcher(b *testing.B) {
+	rules, err := Parse("09:00-17:00 1-5 * *; 22:00-02:00 5L * *; TZ=Europe/Berlin 12:00-13:00 * 1,15 *")
+	if err != nil {
+		b.Fatal(err)
+	}
+	m := Compile(rules)
+	tm := time.Date(2024, 3, 31, 20, 30, 0, 0, time.UTC)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.Match(tm)
+	}
+}
@@ -0,0 +1,382 @@
+package cronrange
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// part is a part of a rule which rules can be merged along
+type part int
+
+const (
+	partTime part = iota
+	partDow
+	partDom
+	partMonth
+	partDates
+)
+
+// Normalize returns an equivalent set of rules in a canonical form, so equivalent schedules written
+// in different ways are likely to get the same representation. It merges rules differing in a single part,
+// e.g. "* 1 * *" and "* 2 * *" into "* 1-2 * *", merges overlapping and adjacent time ranges and dates,
+// removes duplicates and rules fully covered by others, and drops options without effect. Positive rules
+// come first and exclusion rules next, each sorted by their string representation.
+// Simplification is conservative: rules are merged or removed only when the result matches the same times
+// on every day, including days with clock changes.
+func Normalize(rules []Rule) []Rule {
+	var include, exclude []Rule
+	for _, r := range rules {
+		r = canonicalRule(r)
+		if r.exclude {
+			exclude = append(exclude, r)
+			continue
+		}
+		include = append(include, r)
+	}
+
+	res := append(simplifyRules(include), simplifyRules(exclude)...)
+	if res == nil {
+		res = []Rule{}
+	}
+	return res
+}
+
+// simplifyRules merges and removes rules until nothing changes, and returns them sorted
+func simplifyRules(rules []Rule) []Rule {
+	rules = dedupeRules(rules)
+	for changed := true; changed; {
+		changed = false
+		for _, p := range []part{partTime, partDow, partDom, partMonth, partDates} {
+			n := len(rules)
+			rules = mergeRules(rules, p)
+			changed = changed || len(rules) < n
+		}
+		n := len(rules)
+		rules = removeCovered(rules)
+		changed = changed || len(rules) < n
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].String() < rules[j].String() })
+	return rules
+}
+
+// canonicalRule returns the rule with options and fields without effect dropped or simplified
+func canonicalRule(r Rule) Rule {
+	if r.daysOr && (r.dom.all || r.dow.all) {
+		r.daysOr = false // OR semantics apply only if both dom and dow are restricted
+	}
+	r.month = canonicalField(r.month)
+	if !r.daysOr {
+		// a restricted field with all values is not the same as * under OR semantics
+		r.dow, r.dom = canonicalField(r.dow), canonicalField(r.dom)
+	}
+	if !containsSpec(r.dom.specs, daySpec{kind: holiday}) {
+		r.cal, r.calName = nil, ""
+	}
+	r.dates = mergeDates(r.dates)
+
+	if start, end := r.timeRange.bounds(); !r.timeRange.all && !r.timeRange.overnight && start == 0 &&
+		end == secondsPerDay && r.dst == DSTWall {
+		r.timeRange = TimeRange{all: true}
+	}
+	if r.timeRange.all {
+		r.dst = DSTWall // all-day windows follow the wall clock regardless of the policy
+	}
+	tr := &r.timeRange
+	tr.hasSeconds = tr.start%time.Minute != 0 || tr.end%time.Minute != 0
+	return r
+}
+
+// canonicalField returns the field as * if it has all valid values
+func canonicalField(f Field) Field {
+	if f.all || f.max <= f.min {
+		return f
+	}
+	full := uint64(1)<<(f.max+1) - uint64(1)<<f.min
+	if f.values&full != full {
+		return f
+	}
+	return Field{all: true, names: f.names, min: f.min, max: f.max}
+}
+
+// mergeDates returns date ranges sorted by start, with overlapping and adjacent ranges merged
+func mergeDates(dates []dateRange) []dateRange {
+	if len(dates) < 2 {
+		return dates
+	}
+	sorted := append([]dateRange(nil), dates...)
+	sortDates(sorted)
+	res := sorted[:1]
+	for _, dr := range sorted[1:] {
+		last := &res[len(res)-1]
+		if dr.from <= last.to+1 {
+			last.to = max(last.to, dr.to)
+			continue
+		}
+		res = append(res, dr)
+	}
+	return res
+}
+
+// dedupeRules removes rules with the same string representation
+func dedupeRules(rules []Rule) []Rule {
+	seen := map[string]bool{}
+	var res []Rule
+	for _, r := range rules {
+		if s := r.String(); !seen[s] {
+			seen[s] = true
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// ruleKey returns the string representation of the rule without the given part, so rules with the same key
+// differ in that part only
+func ruleKey(r Rule, skip part) string {
+	parts := []string{r.prefix()}
+	for p, s := range []string{r.timeRange.String(), r.dow.String(), r.dom.String(), r.month.String(),
+		formatDates(r.dates)} {
+		if part(p) != skip {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// mergeRules merges rules which differ in the given part only
+func mergeRules(rules []Rule, p part) []Rule {
+	var keys []string
+	groups := map[string][]Rule{}
+	for _, r := range rules {
+		key := ruleKey(r, p)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], r)
+	}
+
+	res := make([]Rule, 0, len(rules))
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) == 1 {
+			res = append(res, group[0])
+			continue
+		}
+		if p == partTime {
+			res = append(res, mergeTimes(group)...)
+			continue
+		}
+		merged := group[0]
+		for _, r := range group[1:] {
+			switch p {
+			case partDow:
+				merged.dow = mergeFields(merged.dow, r.dow)
+			case partDom:
+				merged.dom = mergeFields(merged.dom, r.dom)
+			case partMonth:
+				merged.month = mergeFields(merged.month, r.month)
+			case partDates:
+				if len(merged.dates) == 0 || len(r.dates) == 0 {
+					merged.dates = nil
+					continue
+				}
+				merged.dates = mergeDates(append(append([]dateRange(nil), merged.dates...), r.dates...))
+			}
+		}
+		res = append(res, canonicalRule(merged))
+	}
+	return res
+}
+
+// mergeFields returns a field matching values of both fields
+func mergeFields(a, b Field) Field {
+	if a.all || b.all {
+		return Field{all: true, names: a.names, min: a.min, max: a.max}
+	}
+	res := a
+	res.values |= b.values
+	res.specs = append([]daySpec(nil), a.specs...)
+	for _, spec := range b.specs {
+		if !containsSpec(res.specs, spec) {
+			res.specs = append(res.specs, spec)
+		}
+	}
+	return res
+}
+
+// mergeTimes merges overlapping and adjacent time ranges of rules which differ in time range only.
+// An all-day rule covers same-day ranges. Overnight ranges and ranges under the elapsed DST policy
+// are kept as is, as they can spill into the next day, which the other rules may not match.
+func mergeTimes(group []Rule) []Rule {
+	var res, same []Rule
+	var allDay *Rule
+	for _, r := range group {
+		switch {
+		case r.timeRange.all:
+			allDay = &r
+		case r.timeRange.overnight || r.dst != DSTWall:
+			res = append(res, r)
+		default:
+			same = append(same, r)
+		}
+	}
+	if allDay != nil {
+		return append(res, *allDay)
+	}
+
+	sort.Slice(same, func(i, j int) bool { return same[i].timeRange.start < same[j].timeRange.start })
+	for i := 0; i < len(same); {
+		cur := same[i]
+		start, end := cur.timeRange.bounds()
+		j := i + 1
+		for ; j < len(same); j++ {
+			s, e := same[j].timeRange.bounds()
+			if s > end {
+				break
+			}
+			if e >= end {
+				end, cur.timeRange.halfOpen = e, same[j].timeRange.halfOpen
+			}
+		}
+		if j > i+1 {
+			if end == secondsPerDay {
+				cur.timeRange.halfOpen = false // the range can't end at 24:00, it ends at 23:59:59 inclusive
+			}
+			cur.timeRange.start, cur.timeRange.end = time.Duration(start)*time.Second, time.Duration(end)*time.Second
+			if !cur.timeRange.halfOpen {
+				cur.timeRange.end -= time.Second // bounds of inclusive ranges end one second past the end time
+			}
+			cur = canonicalRule(cur)
+		}
+		res = append(res, cur)
+		i = j
+	}
+	return res
+}
+
+// removeCovered removes rules matching only times matched by another rule of the set
+func removeCovered(rules []Rule) []Rule {
+	removed := make([]bool, len(rules))
+	for i := range rules {
+		for j := range rules {
+			if i != j && !removed[j] && covers(rules[j], rules[i]) {
+				removed[i] = true
+				break
+			}
+		}
+	}
+	var res []Rule
+	for i, r := range rules {
+		if !removed[i] {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// covers conservatively checks if rule b matches every time matched by rule a
+func covers(b, a Rule) bool {
+	if a.exclude != b.exclude || locationName(a.loc) != locationName(b.loc) {
+		return false
+	}
+	if b.timeRange.all {
+		// windows of a spilling over midnight belong to the start day, but b must match the next day too
+		spills := a.timeRange.overnight || (a.dst == DSTElapsed && !a.timeRange.all)
+		if spills && !everyDay(b) {
+			return false
+		}
+	}
+	if !b.timeRange.all && (a.timeRange.all || a.dst != DSTWall || b.dst != DSTWall ||
+		!timeRangeCovers(b.timeRange, a.timeRange)) {
+		return false
+	}
+	if !fieldCovers(b.month, a.month) || !datesCover(b.dates, a.dates) {
+		return false
+	}
+	if b.dom.all && b.dow.all {
+		return true
+	}
+	if effectiveOr(a) || effectiveOr(b) {
+		return false
+	}
+	if containsSpec(a.dom.specs, daySpec{kind: holiday}) && !b.dom.all && a.calName != b.calName {
+		return false
+	}
+	return fieldCovers(b.dom, a.dom) && fieldCovers(b.dow, a.dow)
+}
+
+// everyDay checks if the rule is not limited to some days
+func everyDay(r Rule) bool {
+	return r.dow.all && r.dom.all && r.month.all && len(r.dates) == 0
+}
+
+// effectiveOr checks if the rule matches days by dom or dow rather than both
+func effectiveOr(r Rule) bool {
+	return r.daysOr && !r.dom.all && !r.dow.all
+}
+
+// locationName returns the name of the rule's location, empty for rules without a timezone
+func locationName(loc *time.Location) string {
+	if loc == nil {
+		return ""
+	}
+	return loc.String()
+}
+
+// timeRangeCovers checks if the wall-clock time range b covers a on the same start day, both not all-day
+func timeRangeCovers(b, a TimeRange) bool {
+	bs, be := b.bounds()
+	as, ae := a.bounds()
+	switch {
+	case a.overnight && !b.overnight:
+		return false
+	case !a.overnight && b.overnight:
+		return as >= bs // a ends by midnight, where the first part of b ends
+	}
+	return bs <= as && ae <= be
+}
+
+// fieldCovers checks if field b matches every value and day matched by field a
+func fieldCovers(b, a Field) bool {
+	if b.all {
+		return true
+	}
+	if a.all || a.values&^b.values != 0 {
+		return false
+	}
+	for _, spec := range a.specs {
+		if containsSpec(b.specs, spec) {
+			continue
+		}
+		// n-th and last weekday of month are covered by the weekday itself
+		if (spec.kind == nthWeekday || spec.kind == lastWeekdayOfMonth) && b.matches(spec.value) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// datesCover checks if dates b include all dates a, nil dates meaning no limit
+func datesCover(b, a []dateRange) bool {
+	if len(b) == 0 {
+		return true
+	}
+	if len(a) == 0 {
+		return false
+	}
+	for _, ar := range a {
+		covered := false
+		for _, br := range b {
+			if br.from <= ar.from && ar.to <= br.to {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
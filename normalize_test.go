@@ -0,0 +1,179 @@
+package cronrange
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	RegisterCalendar("test-normalize", NewHolidays(time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)))
+
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{name: "single rule", expr: "09:00-17:00 1-5 * *", want: "09:00-17:00 1-5 * *"},
+		{name: "duplicates", expr: "09:00-17:00 1-5 * *; 09:00-17:00 mon-fri * *", want: "09:00-17:00 1-5 * *"},
+		{name: "days", expr: "09:00-17:00 1 * *; 09:00-17:00 2 * *; 09:00-17:00 3-5 * *", want: "09:00-17:00 1-5 * *"},
+		{name: "all weekdays", expr: "* 0-3 * *; * 4-6 * *", want: "* * * *"},
+		{name: "months", expr: "* * 1 jan-jun; * * 1 jul-dec", want: "* * 1 *"},
+		{name: "days of month with specifiers", expr: "* * 1 *; * * L *", want: "* * 1,L *"},
+		{
+			name: "overlapping times",
+			expr: "09:00-12:00 1-5 * *; 11:00-14:00 1-5 * *; 14:00-17:00) 1-5 * *",
+			want: "09:00-17:00) 1-5 * *",
+		},
+		{name: "adjacent times", expr: "09:00-11:59:59 * * *; 12:00-13:00 * * *", want: "09:00-13:00 * * *"},
+		{
+			name: "separate times",
+			expr: "12:00-13:00 * * *; 09:00-10:00 * * *",
+			want: "09:00-10:00 * * *; 12:00-13:00 * * *",
+		},
+		{name: "times up to midnight", expr: "00:00-11:59:59 * * *; 12:00-23:59:59 * * *", want: "* * * *"},
+		{
+			name: "half-open till midnight",
+			expr: "12:00-13:00) * * *; 13:00-23:59:59 * * *",
+			want: "12:00:00-23:59:59 * * *",
+		},
+		{
+			name: "merge along two parts",
+			expr: "09:00-12:00 1 * *; 12:00-17:00 1 * *; 09:00-17:00 2 * *",
+			want: "09:00-17:00 1-2 * *",
+		},
+		{name: "covered time", expr: "09:00-17:00 * * *; 10:00-11:00 1 * *", want: "09:00-17:00 * * *"},
+		{name: "covered by overnight", expr: "22:00-06:00 * * *; 23:00-23:30 5 * *", want: "22:00-06:00 * * *"},
+		{name: "covered overnight", expr: "21:00-07:00 * * *; 22:00-06:00 1-5 * *", want: "21:00-07:00 * * *"},
+		{
+			name: "overnight not covered by all-day on some days",
+			expr: "* 1-5 * *; 22:00-06:00 5 * *",
+			want: "* 1-5 * *; 22:00-06:00 5 * *",
+		},
+		{name: "overnight spilling past all-day", expr: "* 1 * *; 22:00-02:00 1 * *", want: "* 1 * *; 22:00-02:00 1 * *"},
+		{name: "overnight spilling past all-day dom", expr: "* * 1 *; 22:00-02:00 * 1 *", want: "* * 1 *; 22:00-02:00 * 1 *"},
+		{name: "overnight covered by always", expr: "* * * *; 22:00-06:00 5 * *", want: "* * * *"},
+		{name: "nth weekday covered by weekday", expr: "* 5 * *; 09:00-10:00 5#3 * *", want: "* 5 * *"},
+		{name: "covered dates", expr: "* * * * 2026; * 1 * * 2026-03-01..2026-03-31", want: "* * * * 2026"},
+		{
+			name: "merged dates",
+			expr: "* 1 * * 2026; * 1 * * 2027; * 1 * * 2029-01-01",
+			want: "* 1 * * 2026-2027,2029-01-01",
+		},
+		{name: "dates and no dates", expr: "* 1 * * 2026; * 1 * *", want: "* 1 * *"},
+		{
+			name: "dates in a rule",
+			expr: "* * * * 2026-12-30..2027-01-05,2026-01-01..2026-12-29",
+			want: "* * * * 2026-01-01..2027-01-05",
+		},
+		{name: "ineffective or", expr: "DAYS=or * 1-5 * *", want: "* 1-5 * *"},
+		{name: "effective or kept", expr: "DAYS=or * 0-6 1 *", want: "DAYS=or * 0-6 1 *"},
+		{name: "or rules merged", expr: "DAYS=or * 1 1 *; DAYS=or * 2 1 *", want: "DAYS=or * 1-2 1 *"},
+		{name: "or not covered", expr: "DAYS=or * 1 1 *; * 1 1 *", want: "* 1 1 *; DAYS=or * 1 1 *"},
+		{name: "elapsed all day", expr: "DST=elapsed * 1 * *", want: "* 1 * *"},
+		{
+			name: "elapsed not merged",
+			expr: "DST=elapsed 01:00-02:00 * * *; DST=elapsed 02:00-03:00 * * *",
+			want: "DST=elapsed 01:00-02:00 * * *; DST=elapsed 02:00-03:00 * * *",
+		},
+		{name: "seconds dropped", expr: "09:00:00-17:00:00 * * *", want: "09:00-17:00 * * *"},
+		{name: "unused calendar", expr: "CAL=test-normalize * 1 * *", want: "* 1 * *"},
+		{name: "different timezones", expr: "TZ=UTC * 1 * *; * 1 * *", want: "* 1 * *; TZ=UTC * 1 * *"},
+		{
+			name: "exclusions kept separately",
+			expr: "!12:00-13:00 * * *; 09:00-17:00 1-5 * *; !12:30-14:00 * * *; * 0,6 * *",
+			want: "* 0,6 * *; 09:00-17:00 1-5 * *; !12:00-14:00 * * *",
+		},
+		{name: "exclusion not covering positive", expr: "* * * *; !* * * *", want: "* * * *; !* * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := Normalize(rules)
+			if s := Rules(got).String(); s != tt.want {
+				t.Errorf("Normalize() = %q, want %q", s, tt.want)
+			}
+
+			// normalized rules are stable and match the same times
+			if s := Rules(Normalize(got)).String(); s != tt.want {
+				t.Errorf("Normalize() of normalized rules = %q, want %q", s, tt.want)
+			}
+			ny, err := time.LoadLocation("America/New_York")
+			if err != nil {
+				t.Fatal(err)
+			}
+			starts := []time.Time{time.Date(2024, 3, 8, 0, 0, 0, 0, ny), time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC)}
+			for _, from := range starts {
+				for tm := from; tm.Before(from.AddDate(0, 0, 10)); tm = tm.Add(13 * time.Minute) {
+					if Match(got, tm) != Match(rules, tm) {
+						t.Fatalf("Match() at %v = %v for normalized rules, %v for original", tm, Match(got, tm), Match(rules, tm))
+					}
+				}
+			}
+		})
+	}
+
+	if got := Normalize(nil); got == nil || len(got) != 0 {
+		t.Errorf("Normalize(nil) = %v, want empty", got)
+	}
+}
+
+// TestNormalizeMatchesSameTimes checks Normalize against Match for random rule sets built from parts
+// likely to merge or cover each other
+func TestNormalizeMatchesSameTimes(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := []string{"", "", "", "!", "DAYS=or ", "DST=elapsed ", "TZ=America/New_York "}
+	times := []string{"*", "*", "09:00-17:00", "12:00-13:00)", "17:00:01-18:00", "22:00-02:00", "23:00-01:00)",
+		"01:00-03:00", "00:00-23:59:59", "02:30-02:45"}
+	dows := []string{"*", "*", "1", "1-5", "0,6", "5", "5#3", "1L"}
+	doms := []string{"*", "*", "1", "1-15", "L", "15W", "31"}
+	months := []string{"*", "*", "*", "3", "1-6", "11,12"}
+	dates := []string{"", "", "", " 2024", " 2024-03-01..2024-03-15", " 2024-11-01..2024-11-30"}
+
+	rnd := rand.New(rand.NewSource(1))
+	pick := func(items []string) string { return items[rnd.Intn(len(items))] }
+	for i := 0; i < 1000; i++ {
+		// rules of a set differ from a common base in a single part, so they merge and cover each other
+		parts := [][]string{options, times, dows, doms, months, dates}
+		base := make([]string, len(parts))
+		for k, items := range parts {
+			base[k] = pick(items)
+		}
+		var exprs []string
+		for n := 1 + rnd.Intn(4); n > 0; n-- {
+			r := append([]string(nil), base...)
+			k := rnd.Intn(len(parts))
+			r[k] = pick(parts[k])
+			exprs = append(exprs, r[0]+strings.Join(r[1:5], " ")+r[5])
+		}
+		expr := strings.Join(exprs, "; ")
+		rules, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("%q: %v", expr, err)
+		}
+		normalized := Normalize(rules)
+
+		// random times of 2024 in both locations, half of them around clock changes and midnight
+		for j := 0; j < 500; j++ {
+			loc := []*time.Location{time.UTC, ny}[j%2]
+			tm := time.Date(2024, 1, 1, 0, 0, 0, 0, loc).Add(time.Duration(rnd.Int63n(int64(366 * 24 * time.Hour))))
+			if j%4 < 2 {
+				day := []time.Time{time.Date(2024, 3, 10, 0, 0, 0, 0, loc), time.Date(2024, 11, 3, 0, 0, 0, 0, loc),
+					time.Date(2024, 3, 1, 0, 0, 0, 0, loc), time.Date(2024, 3, 16, 0, 0, 0, 0, loc)}[rnd.Intn(4)]
+				tm = day.Add(time.Duration(rnd.Int63n(int64(27*time.Hour))) - 2*time.Hour).Truncate(time.Second)
+			}
+			if Match(rules, tm) != Match(normalized, tm) {
+				t.Fatalf("%q normalized to %q: Match() at %v = %v, want %v",
+					expr, Rules(normalized).String(), tm, Match(normalized, tm), Match(rules, tm))
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package cronrange
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// Overlap is a conflict of two named rule sets, with the intervals when both of them are active
+type Overlap struct {
+	A, B      string     // names of the rule sets, A sorts before B
+	Intervals []Interval // merged, non-overlapping intervals when both rule sets are active, in order
+}
+
+// Duration returns the total length of the overlapping intervals
+func (o Overlap) Duration() time.Duration {
+	var res time.Duration
+	for _, iv := range o.Intervals {
+		res += iv.Duration()
+	}
+	return res
+}
+
+// Overlaps finds every pair of named rule sets active at the same time within [from, to), e.g. maintenance
+// windows of different teams sharing a resource. Sets can come from ParseDefinitions. Pairs are sorted
+// by names, pairs without overlaps are not reported. Intervals are computed as by Intervals, in the location
+// of from, and rules without a timezone are evaluated in it as well.
+func Overlaps(sets map[string][]Rule, from, to time.Time) []Overlap {
+	names := make([]string, 0, len(sets))
+	for name := range sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// spans of every set are computed once and intersected for each pair
+	spans := make([][]span, len(names))
+	for i, name := range names {
+		spans[i] = slices.Collect(activeSpans(sets[name], from, to))
+	}
+
+	var res []Overlap
+	for i := range names {
+		for j := i + 1; j < len(names); j++ {
+			common := intersectSpans(slices.Values(spans[i]), slices.Values(spans[j]))
+			intervals := slices.Collect(intervalsOf(common, from, to))
+			if len(intervals) > 0 {
+				res = append(res, Overlap{A: names[i], B: names[j], Intervals: intervals})
+			}
+		}
+	}
+	return res
+}
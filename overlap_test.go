@@ -0,0 +1,53 @@
+package cronrange
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOverlaps(t *testing.T) {
+	sets, err := ParseDefinitions(strings.NewReader(`
+db = 01:00-03:00 * * *
+network = 02:30-04:00 2,4 * *; 23:00-01:30 5 * *
+storage = 05:00-06:00 * * *
+backup = TZ=Europe/Berlin 03:00-03:59:59 * * *
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) // Monday
+	to := from.AddDate(0, 0, 7)
+	got := Overlaps(sets, from, to)
+
+	want := []string{
+		"backup-db: [2024-01-01T02:00:00Z, 2024-01-01T03:00:00Z) [2024-01-02T02:00:00Z, 2024-01-02T03:00:00Z) " +
+			"[2024-01-03T02:00:00Z, 2024-01-03T03:00:00Z) [2024-01-04T02:00:00Z, 2024-01-04T03:00:00Z) " +
+			"[2024-01-05T02:00:00Z, 2024-01-05T03:00:00Z) [2024-01-06T02:00:00Z, 2024-01-06T03:00:00Z) " +
+			"[2024-01-07T02:00:00Z, 2024-01-07T03:00:00Z) 7h0m0s",
+		"backup-network: [2024-01-02T02:30:00Z, 2024-01-02T03:00:00Z) [2024-01-04T02:30:00Z, 2024-01-04T03:00:00Z) 1h0m0s",
+		"db-network: [2024-01-02T02:30:00Z, 2024-01-02T03:00:01Z) [2024-01-04T02:30:00Z, 2024-01-04T03:00:01Z) " +
+			"[2024-01-06T01:00:00Z, 2024-01-06T01:30:01Z) 1h30m3s",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Overlaps() = %v, want %d overlaps", got, len(want))
+	}
+	for i, o := range got {
+		var ivs []string
+		for _, iv := range o.Intervals {
+			ivs = append(ivs, iv.String())
+		}
+		if s := fmt.Sprintf("%s-%s: %s %v", o.A, o.B, strings.Join(ivs, " "), o.Duration()); s != want[i] {
+			t.Errorf("Overlaps()[%d] = %s, want %s", i, s, want[i])
+		}
+	}
+
+	if got := Overlaps(map[string][]Rule{"db": sets["db"], "storage": sets["storage"]}, from, to); len(got) != 0 {
+		t.Errorf("Overlaps() of disjoint sets = %v, want none", got)
+	}
+	if got := Overlaps(nil, from, to); len(got) != 0 {
+		t.Errorf("Overlaps(nil) = %v, want none", got)
+	}
+}
@@ -6,14 +6,42 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // Rule represents a single cronrange rule
 type Rule struct {
 	timeRange TimeRange
-	dow       Field // 0-6 (Sunday = 0)
-	dom       Field // 1-31
-	month     Field // 1-12
+	dow       Field          // 0-6 (Sunday = 0)
+	dom       Field          // 1-31
+	month     Field          // 1-12
+	loc       *time.Location // nil means the location of the checked time
+	dst       DSTPolicy
+	daysOr    bool        // dom and dow are ORed if both are restricted, like in classic cron
+	dates     []dateRange // optional absolute days the rule is limited to, nil for no limit
+	exclude   bool        // exclusion rule, the time must not match it
+	cal       Calendar    // holidays for the H specifier, nil if the rule has no calendar
+	calName   string      // name of the calendar in the registry
+}
+
+// DSTPolicy defines how a time range behaves on days when the wall clock skips or repeats an hour
+type DSTPolicy int
+
+const (
+	// DSTWall matches wall-clock readings: skipped readings never match and repeated readings match
+	// twice, so the window gets shorter or longer by the size of the clock change
+	DSTWall DSTPolicy = iota
+	// DSTElapsed opens the window when the wall clock reaches the start time for the first time, or at
+	// the clock change if the start time is skipped, and keeps it open for the nominal length of the range
+	DSTElapsed
+)
+
+// String returns the string representation of a DSTPolicy as used in rule options
+func (p DSTPolicy) String() string {
+	if p == DSTElapsed {
+		return "elapsed"
+	}
+	return "wall"
 }
 
 // TimeRange represents a time period within a day
@@ -23,69 +51,163 @@ type TimeRange struct {
 	all        bool
 	overnight  bool // true if range spans across midnight
 	hasSeconds bool // track if the original format included seconds
+	halfOpen   bool // true if the end time is exclusive
 }
 
 // Field represents a cronrange field that can contain multiple values
 type Field struct {
-	values map[int]bool
+	values uint64 // bit n is set if value n is allowed
 	all    bool
+	names  []string  // value names indexed by value, nil if the field has no names
+	min    int       // smallest valid value, used to render steps
+	max    int       // largest valid value, used to render steps
+	specs  []daySpec // month-dependent day specifiers, like L or 5#3
 }
 
-// parseRule parses a cronrange rule string and returns a Rule struct or an error if the input is invalid
+var (
+	dowNames   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+	monthNames = []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+)
+
+// parseRule parses a cronrange rule string and returns a Rule struct or an error if the input is invalid.
+// The rule may start with KEY=value options, e.g. TZ=Europe/Berlin, DST=elapsed or DAYS=or,
+// followed by the four fields and an optional fifth field with years and absolute dates.
+// A rule starting with "!" is an exclusion rule. Errors are always *ParseError with offsets within the rule.
 func parseRule(rule string) (Rule, error) {
-	parts := strings.Fields(rule)
-	if len(parts) != 4 {
-		return Rule{}, fmt.Errorf("rule must have 4 fields: time dow dom month")
+	var res Rule
+	body, exclude := strings.CutPrefix(rule, "!")
+	res.exclude = exclude
+	parts := tokenize(body, len(rule)-len(body))
+
+	for len(parts) > 0 && strings.Contains(parts[0].text, "=") {
+		if err := res.parseOption(parts[0].text); err != nil {
+			return Rule{}, newParseError(rule, "option", parts[0], err)
+		}
+		parts = parts[1:]
 	}
 
-	timeRange, err := parseTimeRange(parts[0])
+	if len(parts) != 4 && len(parts) != 5 {
+		return Rule{}, &ParseError{Input: rule, Err: badFormat("rule must have 4 or 5 fields: time dow dom month [dates]")}
+	}
+
+	timeRange, err := parseTimeRange(parts[0].text)
 	if err != nil {
-		return Rule{}, err
+		return Rule{}, newParseError(rule, "time", parts[0], err)
 	}
 
-	dow, err := parseField(parts[1], 0, 6)
+	dow, err := parseSpecField(parts[1].text, 0, 6, dowNames, parseDowSpec)
 	if err != nil {
-		return Rule{}, fmt.Errorf("invalid dow: %w", err)
+		return Rule{}, newParseError(rule, "dow", parts[1], err)
 	}
 
-	dom, err := parseField(parts[2], 1, 31)
+	dom, err := parseSpecField(parts[2].text, 1, 31, nil, parseDomSpec)
 	if err != nil {
-		return Rule{}, fmt.Errorf("invalid dom: %w", err)
+		return Rule{}, newParseError(rule, "dom", parts[2], err)
 	}
 
-	month, err := parseField(parts[3], 1, 12)
+	month, err := parseField(parts[3].text, 1, 12, monthNames)
 	if err != nil {
-		return Rule{}, fmt.Errorf("invalid month: %w", err)
+		return Rule{}, newParseError(rule, "month", parts[3], err)
 	}
 
-	return Rule{
-		timeRange: timeRange,
-		dow:       dow,
-		dom:       dom,
-		month:     month,
-	}, nil
+	if res.cal == nil && containsSpec(dom.specs, daySpec{kind: holiday}) {
+		return Rule{}, newParseError(rule, "dom", parts[2], badFormat("H requires a calendar set with CAL option"))
+	}
+
+	if len(parts) == 5 {
+		if res.dates, err = parseDates(parts[4].text); err != nil {
+			return Rule{}, newParseError(rule, "dates", parts[4], err)
+		}
+	}
+
+	res.timeRange, res.dow, res.dom, res.month = timeRange, dow, dom, month
+	return res, nil
+}
+
+// tokenize splits s into whitespace-separated tokens, with offsets in s increased by base
+func tokenize(s string, base int) []token {
+	var res []token
+	start := -1
+	for i, c := range s {
+		if !unicode.IsSpace(c) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			res = append(res, token{text: s[start:i], pos: base + start})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		res = append(res, token{text: s[start:], pos: base + start})
+	}
+	return res
+}
+
+// parseOption parses a KEY=value rule option and sets it on the rule
+func (r *Rule) parseOption(opt string) error {
+	key, val, _ := strings.Cut(opt, "=")
+	switch key {
+	case "TZ":
+		loc, err := time.LoadLocation(val)
+		if err != nil || val == "" {
+			return badFormat("invalid timezone %q", val)
+		}
+		r.loc = loc
+	case "DST":
+		switch val {
+		case "wall":
+			r.dst = DSTWall
+		case "elapsed":
+			r.dst = DSTElapsed
+		default:
+			return badFormat("invalid DST policy %q, must be wall or elapsed", val)
+		}
+	case "DAYS":
+		switch val {
+		case "and":
+			r.daysOr = false
+		case "or":
+			r.daysOr = true
+		default:
+			return badFormat("invalid days semantics %q, must be and or or", val)
+		}
+	case "CAL":
+		cal, ok := lookupCalendar(val)
+		if !ok {
+			return badFormat("unknown calendar %q", val)
+		}
+		r.cal, r.calName = cal, val
+	default:
+		return badFormat("unknown option %q", key)
+	}
+	return nil
 }
 
 // parseTimeRange parses a time range string in the following formats: HH:MM-HH:MM, HH:MM:SS-HH:MM:SS
-// or a single asterisk for all day. Handles ranges that span across midnight.
+// or a single asterisk for all day. Handles ranges that span across midnight. A trailing ")"
+// makes the end time exclusive, e.g. 09:00-17:00) ends right before 17:00.
 func parseTimeRange(s string) (TimeRange, error) {
 	if s == "*" {
 		return TimeRange{all: true}, nil
 	}
 
+	s, halfOpen := strings.CutSuffix(s, ")")
 	parts := strings.Split(s, "-")
 	if len(parts) != 2 {
-		return TimeRange{}, fmt.Errorf("invalid time range format")
+		return TimeRange{}, badFormat("invalid time range format")
 	}
 
 	start, hasStartSeconds, err := parseTime(parts[0])
 	if err != nil {
-		return TimeRange{}, err
+		return TimeRange{}, atPart(0, parts[0], err)
 	}
 
 	end, hasEndSeconds, err := parseTime(parts[1])
 	if err != nil {
-		return TimeRange{}, err
+		return TimeRange{}, atPart(len(parts[0])+1, parts[1], err)
 	}
 
 	// Check if this is an overnight range
@@ -99,6 +221,7 @@ func parseTimeRange(s string) (TimeRange, error) {
 		end:        end,
 		overnight:  overnight,
 		hasSeconds: hasStartSeconds || hasEndSeconds,
+		halfOpen:   halfOpen,
 	}, nil
 }
 
@@ -108,17 +231,17 @@ func parseTimeRange(s string) (TimeRange, error) {
 func parseTime(s string) (time.Duration, bool, error) {
 	parts := strings.Split(s, ":")
 	if len(parts) < 2 || len(parts) > 3 { // ensure the time string has either 2 or 3 parts
-		return 0, false, fmt.Errorf("invalid time format")
+		return 0, false, badFormat("invalid time format")
 	}
 
 	hours, err := strconv.Atoi(parts[0]) // convert the first part to hours
 	if err != nil {
-		return 0, false, err
+		return 0, false, badFormat("invalid hours %q", parts[0])
 	}
 
 	minutes, err := strconv.Atoi(parts[1]) // convert the second part to minutes
 	if err != nil {
-		return 0, false, err
+		return 0, false, badFormat("invalid minutes %q", parts[1])
 	}
 
 	seconds := 0
@@ -126,118 +249,318 @@ func parseTime(s string) (time.Duration, bool, error) {
 	if hasSeconds {
 		seconds, err = strconv.Atoi(parts[2]) // convert the third part to seconds
 		if err != nil {
-			return 0, false, err
+			return 0, false, badFormat("invalid seconds %q", parts[2])
 		}
 	}
 
 	if hours < 0 || hours > 23 || minutes < 0 || minutes > 59 || seconds < 0 || seconds > 59 { // validate the time values
-		return 0, false, fmt.Errorf("invalid time values")
+		return 0, false, outOfRange("invalid time values")
 	}
 
 	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second, hasSeconds, nil
 }
 
 // parseField parses a field string in the following formats: 1,2,3, 1-3,5-6 or a single asterisk for all values.
-// The min and max arguments define the range of valid values for the field. The function returns a Field with
-// the parsed values or an error if the input is invalid. Values in the Field are stored in a map
-// for fast lookup of allowed values.
-func parseField(s string, min, max int) (Field, error) {
+// Ranges and asterisk can have a step, e.g. */2 or 1-5/2, and a single value with a step, e.g. 3/2, runs
+// from the value to max. The min and max arguments define the range of valid values for the field.
+// If names are given, values can also be set by case-insensitive names, e.g. mon-fri, where names are
+// indexed by value. The function returns a Field with the parsed values or an error if the input is invalid.
+// Values in the Field are stored as a bitset for fast lookup of allowed values.
+func parseField(s string, min, max int, names []string) (Field, error) {
 	if s == "*" {
-		return Field{all: true}, nil
+		return Field{all: true, names: names, min: min, max: max}, nil
 	}
 
-	values := make(map[int]bool)
-	ranges := strings.Split(s, ",")
-
-	for _, r := range ranges {
-		if strings.Contains(r, "-") {
-			parts := strings.Split(r, "-")
-			if len(parts) != 2 {
-				return Field{}, fmt.Errorf("invalid range format")
-			}
+	var values uint64
+	pos := 0
+	for _, item := range strings.Split(s, ",") {
+		bits, err := parseFieldItem(item, min, max, names)
+		if err != nil {
+			return Field{}, atPart(pos, item, err)
+		}
+		values |= bits
+		pos += len(item) + 1
+	}
 
-			start, err := strconv.Atoi(parts[0])
-			if err != nil {
-				return Field{}, err
-			}
+	return Field{values: values, names: names, min: min, max: max}, nil
+}
 
-			end, err := strconv.Atoi(parts[1])
-			if err != nil {
-				return Field{}, err
-			}
+// parseFieldItem parses a single item of a field list, a value or a range with an optional step,
+// and returns the bitset of its values
+func parseFieldItem(item string, min, max int, names []string) (uint64, error) {
+	r, stepStr, hasStep := strings.Cut(item, "/")
+	step := 1
+	if hasStep {
+		var err error
+		if step, err = strconv.Atoi(stepStr); err != nil {
+			return 0, badFormat("invalid step %q", stepStr)
+		}
+		if step < 1 {
+			return 0, outOfRange("invalid step %q", stepStr)
+		}
+	}
 
-			if start < min || end > max || start > end {
-				return Field{}, fmt.Errorf("values out of range")
-			}
+	var start, end int
+	switch {
+	case r == "*" && hasStep:
+		start, end = min, max
+	case strings.Contains(r, "-"):
+		parts := strings.Split(r, "-")
+		if len(parts) != 2 {
+			return 0, badFormat("invalid range format")
+		}
 
-			for i := start; i <= end; i++ {
-				values[i] = true
-			}
-			continue
+		var err error
+		if start, err = parseFieldValue(parts[0], names); err != nil {
+			return 0, err
+		}
+		if end, err = parseFieldValue(parts[1], names); err != nil {
+			return 0, err
 		}
 
-		val, err := strconv.Atoi(r)
+		if start < min || end > max || start > end {
+			return 0, outOfRange("values out of range")
+		}
+	default:
+		val, err := parseFieldValue(r, names)
 		if err != nil {
-			return Field{}, err
+			return 0, err
 		}
 
 		if val < min || val > max {
-			return Field{}, fmt.Errorf("value out of range")
+			return 0, outOfRange("value out of range")
+		}
+		start, end = val, val
+		if hasStep {
+			end = max
 		}
+	}
 
-		values[val] = true
+	var values uint64
+	for i := start; i <= end; i += step {
+		values |= 1 << i
 	}
+	return values, nil
+}
 
-	return Field{values: values}, nil
+// parseFieldValue parses a single field value, either a number or one of the names
+func parseFieldValue(s string, names []string) (int, error) {
+	for i, name := range names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, badFormat("invalid value %q", s)
+	}
+	return v, nil
 }
 
 // matches checks if the current time falls within the time range,
-// handling ranges that span across midnight
+// handling ranges that span across midnight. If the rule has a timezone,
+// the time is converted to it before checking the fields.
 func (r Rule) matches(t time.Time) bool {
-	if !r.month.matches(int(t.Month())) {
-		return false
+	if r.loc != nil {
+		t = t.In(r.loc)
 	}
 
-	if !r.dom.matches(t.Day()) {
-		return false
+	if r.dst == DSTElapsed && !r.timeRange.all {
+		return r.windowsContain(t)
 	}
 
-	if !r.dow.matches(int(t.Weekday())) {
-		return false
-	}
+	days, secs := wallClock(t)
+	return r.matchesWall(days, secs)
+}
 
+// matchesWall checks if the wall-clock reading, given as the calendar day in days since 1970-01-01
+// and seconds since midnight, falls within the time range on a matching day. It ignores the DST policy.
+func (r Rule) matchesWall(days, secs int64) bool {
+	today := civilFromDays(days)
 	if r.timeRange.all {
-		return true
+		return r.dayMatches(today)
 	}
 
-	currentTime := time.Duration(t.Hour())*time.Hour +
-		time.Duration(t.Minute())*time.Minute +
-		time.Duration(t.Second())*time.Second
+	currentTime := time.Duration(secs) * time.Second
 
 	if r.timeRange.overnight {
-		// for overnight ranges (e.g. 23:00-02:00)
-		// the time matches if it's:
-		// - after or equal to start time (e.g. >= 23:00) OR
-		// - before or equal to end time (e.g. <= 02:00)
-		return currentTime >= r.timeRange.start || currentTime <= r.timeRange.end
+		// for overnight ranges (e.g. 23:00-02:00) the time matches if it's:
+		// - after or equal to start time (e.g. >= 23:00) on a matching day OR
+		// - before or equal to end time (e.g. <= 02:00) on the day after a matching day,
+		// as the part after midnight belongs to the window started the day before
+		if currentTime >= r.timeRange.start && r.dayMatches(today) {
+			return true
+		}
+		return r.timeRange.beforeEnd(currentTime) && r.dayMatches(civilFromDays(days-1))
 	}
 
 	// For same-day ranges, time must be between start and end
-	return currentTime >= r.timeRange.start && currentTime <= r.timeRange.end
+	return currentTime >= r.timeRange.start && r.timeRange.beforeEnd(currentTime) && r.dayMatches(today)
+}
+
+// beforeEnd checks if the time since midnight is before the end of the range,
+// including the end time itself unless the range is half-open
+func (tr TimeRange) beforeEnd(d time.Duration) bool {
+	if tr.halfOpen {
+		return d < tr.end
+	}
+	return d <= tr.end
 }
 
 func (f Field) matches(val int) bool {
-	return f.all || f.values[val]
+	return f.all || val >= 0 && val < 64 && f.values&(1<<val) != 0
+}
+
+// matchesDay checks if the field matches the value of the calendar day, or any of the day specifiers
+// of the field matches the day. The calendar can be nil.
+func (f Field) matchesDay(day civil, val int, cal Calendar) bool {
+	if f.matches(val) {
+		return true
+	}
+	for _, spec := range f.specs {
+		if spec.matches(day, cal) {
+			return true
+		}
+	}
+	return false
+}
+
+// dayMatches checks if the calendar day satisfies month, dom and dow fields.
+// Both dom and dow must match, unless the rule uses OR semantics and both fields are restricted,
+// in which case either of them is enough.
+func (r Rule) dayMatches(day civil) bool {
+	if !r.month.matches(int(day.month)) || !r.datesMatch(day.days) {
+		return false
+	}
+	dom, dow := r.dom.matchesDay(day, day.day, r.cal), r.dow.matchesDay(day, int(day.weekday), r.cal)
+	if r.daysOr && !r.dom.all && !r.dow.all {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// datesMatch checks if the calendar day, given as days since 1970-01-01, is within the dates of the rule
+func (r Rule) datesMatch(days int64) bool {
+	if len(r.dates) == 0 {
+		return true
+	}
+	for _, dr := range r.dates {
+		if dr.contains(days) {
+			return true
+		}
+	}
+	return false
+}
+
+// windowsContain checks if t falls into the windows of the rule on its calendar day or the day before,
+// as windows following elapsed time can spill over midnight
+func (r Rule) windowsContain(t time.Time) bool {
+	var arr [4]span
+	days, _ := wallClock(t)
+	buf := r.windows(civilFromDays(days-1), t.Location(), arr[:0])
+	buf = r.windows(civilFromDays(days), t.Location(), buf)
+	for _, s := range buf {
+		if t.Unix() >= s.start && t.Unix() < s.end {
+			return true
+		}
+	}
+	return false
+}
+
+// windows appends to buf the spans of absolute time when the rule is active on the given calendar day.
+// The day is interpreted as a wall-clock date in loc. Windows of overnight
+// ranges start on the given day and end on the next one.
+func (r Rule) windows(day civil, loc *time.Location, buf []span) []span {
+	if !r.dayMatches(day) {
+		return buf
+	}
+
+	midnight := day.days * secondsPerDay
+	if r.timeRange.all {
+		return wallSpans(buf, midnight, midnight+secondsPerDay, loc)
+	}
+
+	start, end := r.timeRange.bounds()
+	if r.timeRange.overnight {
+		end += secondsPerDay
+	}
+	return r.window(buf, midnight+start, midnight+end, loc)
+}
+
+// window appends to buf the spans of absolute time for the wall-clock period [lo, hi) according to
+// the DST policy of the rule
+func (r Rule) window(buf []span, lo, hi int64, loc *time.Location) []span {
+	if lo >= hi {
+		return buf
+	}
+	if r.dst != DSTElapsed {
+		return wallSpans(buf, lo, hi, loc)
+	}
+
+	// the first moment the wall clock shows lo or later, within a day to get over any clock change
+	var arr [2]span
+	first := wallSpans(arr[:0], lo, lo+secondsPerDay, loc)
+	if len(first) == 0 {
+		return buf
+	}
+	return appendSpan(buf, span{start: first[0].start, end: first[0].start + hi - lo})
+}
+
+// bounds returns start and end of the time range in seconds since midnight. The end is exclusive,
+// i.e. one second past the end time of the range unless the range is half-open.
+func (tr TimeRange) bounds() (start, end int64) {
+	start, end = int64(tr.start/time.Second), int64(tr.end/time.Second)
+	if !tr.halfOpen {
+		end++
+	}
+	return start, end
 }
 
 // String returns the string representation of a Rule
 func (r Rule) String() string {
-	return fmt.Sprintf("%s %s %s %s",
+	return r.format(false)
+}
+
+// Named returns the string representation of a Rule with day and month names instead of numbers,
+// e.g. "09:00-17:00 mon-fri * jan-mar"
+func (r Rule) Named() string {
+	return r.format(true)
+}
+
+// format returns the string representation of a Rule, using day and month names if named is set
+func (r Rule) format(named bool) string {
+	var suffix string
+	if len(r.dates) > 0 {
+		suffix = " " + formatDates(r.dates)
+	}
+	return r.prefix() + fmt.Sprintf("%s %s %s %s",
 		r.timeRange.String(),
-		r.dow.String(),
-		r.dom.String(),
-		r.month.String(),
-	)
+		r.dow.format(named),
+		r.dom.format(named),
+		r.month.format(named),
+	) + suffix
+}
+
+// prefix returns the exclusion mark and options of the rule as rendered before the fields,
+// each option followed by a space
+func (r Rule) prefix() string {
+	var prefix string
+	if r.exclude {
+		prefix = "!"
+	}
+	if r.loc != nil {
+		prefix += "TZ=" + r.loc.String() + " "
+	}
+	if r.dst != DSTWall {
+		prefix += "DST=" + r.dst.String() + " "
+	}
+	if r.daysOr {
+		prefix += "DAYS=or "
+	}
+	if r.cal != nil {
+		prefix += "CAL=" + r.calName + " "
+	}
+	return prefix
 }
 
 // String returns the string representation of a TimeRange
@@ -253,30 +576,72 @@ func (tr TimeRange) String() string {
 	endM := (tr.end % time.Hour) / time.Minute
 	endS := (tr.end % time.Minute) / time.Second
 
+	var suffix string
+	if tr.halfOpen {
+		suffix = ")"
+	}
+
 	if tr.hasSeconds {
-		return fmt.Sprintf("%02d:%02d:%02d-%02d:%02d:%02d",
-			startH, startM, startS, endH, endM, endS)
+		return fmt.Sprintf("%02d:%02d:%02d-%02d:%02d:%02d%s",
+			startH, startM, startS, endH, endM, endS, suffix)
 	}
-	return fmt.Sprintf("%02d:%02d-%02d:%02d", startH, startM, endH, endM)
+	return fmt.Sprintf("%02d:%02d-%02d:%02d%s", startH, startM, endH, endM, suffix)
 }
 
 // String returns the string representation of a Field
 func (f Field) String() string {
+	return f.format(false)
+}
+
+// Named returns the string representation of a Field with day or month names instead of numbers,
+// e.g. mon-fri. Fields without names, like dom, are rendered the same way as String does.
+func (f Field) Named() string {
+	return f.format(true)
+}
+
+// format returns the string representation of a Field, using value names if named is set
+func (f Field) format(named bool) string {
 	if f.all {
 		return "*"
 	}
 
-	// get all values from the map
-	var vals []int
-	for v := range f.values {
-		vals = append(vals, v)
+	vals := f.Values()
+	if len(vals) == 0 && len(f.specs) == 0 {
+		return "*"
+	}
+
+	// day specifiers follow the values in a stable order
+	ranges := f.formatValues(vals, named)
+	specs := make([]string, 0, len(f.specs))
+	for _, spec := range f.specs {
+		specs = append(specs, spec.format(named))
 	}
+	sort.Strings(specs)
+
+	return strings.Join(append(ranges, specs...), ",")
+}
+
+// formatValues returns sorted values as a list of single values, ranges and ranges with step
+func (f Field) formatValues(vals []int, named bool) []string {
 	if len(vals) == 0 {
-		return "*"
+		return nil
 	}
 
-	// sort values
-	sort.Ints(vals)
+	val := func(v int) string {
+		if named && v < len(f.names) && f.names[v] != "" {
+			return f.names[v]
+		}
+		return strconv.Itoa(v)
+	}
+
+	// values with the same step between them, e.g. 1,3,5,7, are rendered as a range with step
+	if step, ok := progression(vals); ok {
+		first, last := vals[0], vals[len(vals)-1]
+		if first == f.min && last+step > f.max {
+			return []string{fmt.Sprintf("*/%d", step)}
+		}
+		return []string{fmt.Sprintf("%s-%s/%d", val(first), val(last), step)}
+	}
 
 	// find ranges and individual values
 	var ranges []string
@@ -287,9 +652,9 @@ func (f Field) String() string {
 		if vals[i] != prev+1 {
 			// end of a range or single value
 			if start == prev {
-				ranges = append(ranges, fmt.Sprintf("%d", start))
+				ranges = append(ranges, val(start))
 			} else {
-				ranges = append(ranges, fmt.Sprintf("%d-%d", start, prev))
+				ranges = append(ranges, val(start)+"-"+val(prev))
 			}
 			start = vals[i]
 		}
@@ -298,10 +663,146 @@ func (f Field) String() string {
 
 	// handle the last range or value
 	if start == prev {
-		ranges = append(ranges, fmt.Sprintf("%d", start))
+		ranges = append(ranges, val(start))
 	} else {
-		ranges = append(ranges, fmt.Sprintf("%d-%d", start, prev))
+		ranges = append(ranges, val(start)+"-"+val(prev))
+	}
+
+	return ranges
+}
+
+// progression checks if sorted values make an arithmetic progression of at least four values
+// with step greater than one, and returns the step. Shorter progressions read better as lists.
+func progression(vals []int) (int, bool) {
+	if len(vals) < 4 {
+		return 0, false
 	}
+	step := vals[1] - vals[0]
+	if step < 2 {
+		return 0, false
+	}
+	for i := 2; i < len(vals); i++ {
+		if vals[i]-vals[i-1] != step {
+			return 0, false
+		}
+	}
+	return step, true
+}
+
+// TimeRange returns the time range of the rule
+func (r Rule) TimeRange() TimeRange {
+	return r.timeRange
+}
+
+// Dow returns the day of week field of the rule, 0-6 with 0 for Sunday
+func (r Rule) Dow() Field {
+	return r.dow
+}
+
+// Dom returns the day of month field of the rule, 1-31
+func (r Rule) Dom() Field {
+	return r.dom
+}
+
+// Month returns the month field of the rule, 1-12
+func (r Rule) Month() Field {
+	return r.month
+}
+
+// Location returns the timezone of the rule, or nil if the rule is evaluated in the location
+// of the checked time
+func (r Rule) Location() *time.Location {
+	return r.loc
+}
+
+// DST returns the DST policy of the rule
+func (r Rule) DST() DSTPolicy {
+	return r.dst
+}
+
+// DaysOr reports whether dom and dow are ORed when both are restricted, like in classic cron
+func (r Rule) DaysOr() bool {
+	return r.daysOr
+}
+
+// Dates returns the absolute dates the rule is limited to, sorted by their first day,
+// or nil if the rule has no such limit
+func (r Rule) Dates() []DateRange {
+	if len(r.dates) == 0 {
+		return nil
+	}
+	res := make([]DateRange, 0, len(r.dates))
+	for _, dr := range r.dates {
+		res = append(res, DateRange{From: civilFromDays(dr.from).time(), To: civilFromDays(dr.to).time()})
+	}
+	return res
+}
+
+// Exclusion reports whether the rule is an exclusion rule, i.e. the time must not match it
+func (r Rule) Exclusion() bool {
+	return r.exclude
+}
+
+// Calendar returns the name of the holiday calendar of the rule, or an empty string if it has none
+func (r Rule) Calendar() string {
+	return r.calName
+}
+
+// Start returns the start time of the range as duration since midnight, zero for all day
+func (tr TimeRange) Start() time.Duration {
+	return tr.start
+}
+
+// End returns the end time of the range as duration since midnight, zero for all day.
+// The end is inclusive unless the range is half-open.
+func (tr TimeRange) End() time.Duration {
+	return tr.end
+}
+
+// All reports whether the range covers the whole day
+func (tr TimeRange) All() bool {
+	return tr.all
+}
 
-	return strings.Join(ranges, ",")
+// Overnight reports whether the range spans across midnight, e.g. 22:00-02:00
+func (tr TimeRange) Overnight() bool {
+	return tr.overnight
+}
+
+// HalfOpen reports whether the end time is exclusive
+func (tr TimeRange) HalfOpen() bool {
+	return tr.halfOpen
+}
+
+// All reports whether the field matches all values, i.e. it is set to *
+func (f Field) All() bool {
+	return f.all
+}
+
+// Values returns the values of the field in ascending order, or nil if the field matches all values
+// or has day specifiers only
+func (f Field) Values() []int {
+	if f.all {
+		return nil
+	}
+	var res []int
+	for v := 0; v < 64; v++ {
+		if f.values&(1<<v) != 0 {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// Specifiers returns the day specifiers of the field, like L, 15W or 5#3, in the same form
+// as they are written in rules
+func (f Field) Specifiers() []string {
+	if len(f.specs) == 0 {
+		return nil
+	}
+	res := make([]string, 0, len(f.specs))
+	for _, spec := range f.specs {
+		res = append(res, spec.format(false))
+	}
+	return res
 }
@@ -32,6 +32,21 @@ func TestParseTimeRange(t *testing.T) {
 			s:    "17:20-21:35",
 			want: "17:20-21:35",
 		},
+		{
+			name: "half-open range",
+			s:    "09:00-17:00)",
+			want: "09:00-17:00)",
+		},
+		{
+			name: "half-open range with seconds",
+			s:    "09:00:00-17:00:30)",
+			want: "09:00:00-17:00:30)",
+		},
+		{
+			name:    "half-open all day",
+			s:       "*)",
+			wantErr: true,
+		},
 		{
 			name:    "invalid format",
 			s:       "9:00to17:00",
@@ -68,12 +83,14 @@ func TestParseTimeRange(t *testing.T) {
 
 func TestParseField(t *testing.T) {
 	tests := []struct {
-		name    string
-		s       string
-		min     int
-		max     int
-		want    string
-		wantErr bool
+		name      string
+		s         string
+		min       int
+		max       int
+		names     []string
+		want      string
+		wantNamed string
+		wantErr   bool
 	}{
 		{
 			name: "all values",
@@ -110,6 +127,136 @@ func TestParseField(t *testing.T) {
 			max:  6,
 			want: "1-3,5-6",
 		},
+		{
+			name:      "day names",
+			s:         "mon-fri",
+			min:       0,
+			max:       6,
+			names:     dowNames,
+			want:      "1-5",
+			wantNamed: "mon-fri",
+		},
+		{
+			name:      "mixed case day names and numbers",
+			s:         "SAT,Sun,3",
+			min:       0,
+			max:       6,
+			names:     dowNames,
+			want:      "0,3,6",
+			wantNamed: "sun,wed,sat",
+		},
+		{
+			name:      "month names",
+			s:         "jan-mar,dec",
+			min:       1,
+			max:       12,
+			names:     monthNames,
+			want:      "1-3,12",
+			wantNamed: "jan-mar,dec",
+		},
+		{
+			name:      "numbers without names",
+			s:         "1-3,7",
+			min:       1,
+			max:       31,
+			want:      "1-3,7",
+			wantNamed: "1-3,7",
+		},
+		{
+			name: "step over all values",
+			s:    "*/2",
+			min:  1,
+			max:  12,
+			want: "*/2",
+		},
+		{
+			name: "step over range",
+			s:    "1-20/7",
+			min:  1,
+			max:  31,
+			want: "1,8,15",
+		},
+		{
+			name: "step over whole range",
+			s:    "1-31/7",
+			min:  1,
+			max:  31,
+			want: "*/7",
+		},
+		{
+			name: "step from value to max",
+			s:    "2/3",
+			min:  1,
+			max:  12,
+			want: "2-11/3",
+		},
+		{
+			name: "short step renders as list",
+			s:    "1-5/2",
+			min:  0,
+			max:  6,
+			want: "1,3,5",
+		},
+		{
+			name:      "step with names",
+			s:         "feb-dec/2",
+			min:       1,
+			max:       12,
+			names:     monthNames,
+			want:      "2-12/2",
+			wantNamed: "feb-dec/2",
+		},
+		{
+			name: "step combined with list",
+			s:    "*/10,5",
+			min:  1,
+			max:  31,
+			want: "1,5,11,21,31",
+		},
+		{
+			name:    "zero step",
+			s:       "*/0",
+			min:     1,
+			max:     12,
+			wantErr: true,
+		},
+		{
+			name:    "invalid step",
+			s:       "1-5/x",
+			min:     1,
+			max:     12,
+			wantErr: true,
+		},
+		{
+			name:    "asterisk in list without step",
+			s:       "*,5",
+			min:     1,
+			max:     12,
+			wantErr: true,
+		},
+		{
+			name:    "names in field without names",
+			s:       "mon",
+			min:     1,
+			max:     31,
+			wantErr: true,
+		},
+		{
+			name:    "unknown name",
+			s:       "mon-fry",
+			min:     0,
+			max:     6,
+			names:   dowNames,
+			wantErr: true,
+		},
+		{
+			name:    "reversed named range",
+			s:       "fri-mon",
+			min:     0,
+			max:     6,
+			names:   dowNames,
+			wantErr: true,
+		},
 		{
 			name:    "out of range",
 			s:       "7",
@@ -135,7 +282,7 @@ func TestParseField(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseField(tt.s, tt.min, tt.max)
+			got, err := parseField(tt.s, tt.min, tt.max, tt.names)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseField() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -146,6 +293,9 @@ func TestParseField(t *testing.T) {
 			if got.String() != tt.want {
 				t.Errorf("parseField() = %v, want %v", got.String(), tt.want)
 			}
+			if tt.wantNamed != "" && got.Named() != tt.wantNamed {
+				t.Errorf("parseField() named = %v, want %v", got.Named(), tt.wantNamed)
+			}
 		})
 	}
 }
@@ -280,6 +430,109 @@ func TestMatches(t *testing.T) {
 			},
 			wantMatch: []bool{false, true, true, true, true, true, false},
 		},
+		{
+			name: "half-open range",
+			rule: "09:00-17:00) * * *",
+			times: []time.Time{
+				time.Date(2024, 1, 1, 8, 59, 59, 0, time.UTC),          // before range
+				time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),            // start of range
+				time.Date(2024, 1, 1, 16, 59, 59, 999999999, time.UTC), // right before end
+				time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC),           // end of range
+			},
+			wantMatch: []bool{false, true, true, false},
+		},
+		{
+			name: "half-open overnight range",
+			rule: "22:00-02:00) 5 * *",
+			times: []time.Time{
+				time.Date(2024, 1, 5, 22, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 6, 1, 59, 59, 0, time.UTC),
+				time.Date(2024, 1, 6, 2, 0, 0, 0, time.UTC),
+			},
+			wantMatch: []bool{true, true, false},
+		},
+		{
+			name: "half-open range until midnight",
+			rule: "22:00-00:00) * * *",
+			times: []time.Time{
+				time.Date(2024, 1, 5, 23, 59, 59, 0, time.UTC),
+				time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC),
+			},
+			wantMatch: []bool{true, false},
+		},
+		{
+			name: "half-open empty range",
+			rule: "10:00-10:00) * * *",
+			times: []time.Time{
+				time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC),
+			},
+			wantMatch: []bool{false},
+		},
+		{
+			name: "overnight range belongs to the start day",
+			rule: "22:00-02:00 5 * *", // Friday night
+			times: []time.Time{
+				time.Date(2024, 1, 5, 1, 0, 0, 0, time.UTC),    // Friday early morning, Thursday night
+				time.Date(2024, 1, 5, 21, 59, 59, 0, time.UTC), // Friday before start
+				time.Date(2024, 1, 5, 22, 30, 0, 0, time.UTC),  // Friday after start
+				time.Date(2024, 1, 6, 1, 30, 0, 0, time.UTC),   // Saturday after midnight
+				time.Date(2024, 1, 6, 2, 0, 0, 0, time.UTC),    // Saturday end
+				time.Date(2024, 1, 6, 2, 0, 1, 0, time.UTC),    // Saturday just after
+				time.Date(2024, 1, 6, 23, 0, 0, 0, time.UTC),   // Saturday night
+			},
+			wantMatch: []bool{false, false, true, true, true, false, false},
+		},
+		{
+			name: "overnight range across month boundary",
+			rule: "23:00-01:00 * 31 *",
+			times: []time.Time{
+				time.Date(2024, 1, 31, 0, 30, 0, 0, time.UTC), // morning of 31st, window of 30th
+				time.Date(2024, 1, 31, 23, 30, 0, 0, time.UTC),
+				time.Date(2024, 2, 1, 0, 30, 0, 0, time.UTC), // after midnight, window of 31st
+				time.Date(2024, 2, 1, 23, 30, 0, 0, time.UTC),
+			},
+			wantMatch: []bool{false, true, true, false},
+		},
+		{
+			name: "dom and dow are ANDed by default",
+			rule: "* 1 13 *", // Monday the 13th
+			times: []time.Time{
+				time.Date(2024, 5, 13, 12, 0, 0, 0, time.UTC), // Monday, 13th
+				time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC),  // Monday, 6th
+				time.Date(2024, 6, 13, 12, 0, 0, 0, time.UTC), // Thursday, 13th
+			},
+			wantMatch: []bool{true, false, false},
+		},
+		{
+			name: "dom and dow ORed",
+			rule: "DAYS=or * 1 13 *", // every Monday and every 13th
+			times: []time.Time{
+				time.Date(2024, 5, 13, 12, 0, 0, 0, time.UTC), // Monday, 13th
+				time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC),  // Monday, 6th
+				time.Date(2024, 6, 13, 12, 0, 0, 0, time.UTC), // Thursday, 13th
+				time.Date(2024, 6, 14, 12, 0, 0, 0, time.UTC), // Friday, 14th
+			},
+			wantMatch: []bool{true, true, true, false},
+		},
+		{
+			name: "OR with unrestricted dow behaves like AND",
+			rule: "DAYS=or * * 13 *",
+			times: []time.Time{
+				time.Date(2024, 5, 13, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC),
+			},
+			wantMatch: []bool{true, false},
+		},
+		{
+			name: "OR still requires month",
+			rule: "DAYS=or * 1 L 1",
+			times: []time.Time{
+				time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), // Wednesday, last day of January
+				time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC),  // Monday in January
+				time.Date(2024, 2, 5, 12, 0, 0, 0, time.UTC),  // Monday in February
+			},
+			wantMatch: []bool{true, true, false},
+		},
 		{
 			name: "specific days",
 			rule: "10:00-12:00 1,3,5 * *", // Mon,Wed,Fri
@@ -344,3 +597,199 @@ func TestMatches(t *testing.T) {
 		})
 	}
 }
+
+func TestDSTPolicy(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	springDay := time.Date(2024, 3, 10, 0, 0, 0, 0, ny) // clock jumps from 02:00 EST to 03:00 EDT
+	fallDay := time.Date(2024, 11, 3, 0, 0, 0, 0, ny)   // clock goes back from 02:00 EDT to 01:00 EST
+
+	tests := []struct {
+		name      string
+		rule      string
+		day       time.Time
+		wantStart time.Time // in UTC
+		wantTotal time.Duration
+	}{
+		{
+			name:      "wall, range across skipped hour is shorter",
+			rule:      "TZ=America/New_York 01:30-03:30 * * *",
+			day:       springDay,
+			wantStart: time.Date(2024, 3, 10, 6, 30, 0, 0, time.UTC),
+			wantTotal: time.Hour + time.Second,
+		},
+		{
+			name:      "elapsed, range across skipped hour keeps nominal length",
+			rule:      "TZ=America/New_York DST=elapsed 01:30-03:30 * * *",
+			day:       springDay,
+			wantStart: time.Date(2024, 3, 10, 6, 30, 0, 0, time.UTC),
+			wantTotal: 2*time.Hour + time.Second,
+		},
+		{
+			name:      "wall, skipped range matches the only existing reading",
+			rule:      "TZ=America/New_York 02:00-03:00 * * *",
+			day:       springDay,
+			wantStart: time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC),
+			wantTotal: time.Second,
+		},
+		{
+			name:      "elapsed, skipped start opens at the clock change",
+			rule:      "TZ=America/New_York DST=elapsed 02:00-03:00 * * *",
+			day:       springDay,
+			wantStart: time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC),
+			wantTotal: time.Hour + time.Second,
+		},
+		{
+			name:      "wall, repeated hour matches twice",
+			rule:      "TZ=America/New_York 01:00-02:00 * * *",
+			day:       fallDay,
+			wantStart: time.Date(2024, 11, 3, 5, 0, 0, 0, time.UTC),
+			wantTotal: 2*time.Hour + time.Second,
+		},
+		{
+			name:      "elapsed, repeated hour opens at the first occurrence",
+			rule:      "TZ=America/New_York DST=elapsed 01:00-02:00 * * *",
+			day:       fallDay,
+			wantStart: time.Date(2024, 11, 3, 5, 0, 0, 0, time.UTC),
+			wantTotal: time.Hour + time.Second,
+		},
+		{
+			name:      "elapsed on a regular day",
+			rule:      "TZ=America/New_York DST=elapsed 01:00-02:00 * * *",
+			day:       time.Date(2024, 11, 4, 0, 0, 0, 0, ny),
+			wantStart: time.Date(2024, 11, 4, 6, 0, 0, 0, time.UTC),
+			wantTotal: time.Hour + time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := parseRule(tt.rule)
+			if err != nil {
+				t.Fatalf("failed to parse rule %q: %v", tt.rule, err)
+			}
+			rules := []Rule{rule}
+
+			var total time.Duration
+			var start time.Time
+			for iv := range Intervals(rules, tt.day, tt.day.Add(24*time.Hour)) {
+				if start.IsZero() {
+					start = iv.Start
+				}
+				total += iv.Duration()
+			}
+			if !start.Equal(tt.wantStart) {
+				t.Errorf("start = %v, want %v", start.UTC(), tt.wantStart)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("total = %v, want %v", total, tt.wantTotal)
+			}
+
+			// match must agree with intervals, the odd step checks different seconds of each minute
+			for tm := tt.day; tm.Before(tt.day.Add(24 * time.Hour)); tm = tm.Add(59 * time.Second) {
+				in := false
+				for iv := range Intervals(rules, tm, tm.Add(time.Second)) {
+					in = iv.Contains(tm)
+				}
+				if got := rule.matches(tm); got != in {
+					t.Fatalf("matches(%v) = %v, in interval = %v", tm, got, in)
+				}
+			}
+		})
+	}
+}
+
+func TestRuleNamed(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{rule: "09:00-17:00 1-5 * 1-3,12", want: "09:00-17:00 mon-fri * jan-mar,dec"},
+		{rule: "* 0,6 1-7 *", want: "* sun,sat 1-7 *"},
+		{rule: "TZ=UTC 22:00-02:00) fri * *", want: "TZ=UTC 22:00-02:00) fri * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := parseRule(tt.rule)
+			if err != nil {
+				t.Fatalf("failed to parse rule %q: %v", tt.rule, err)
+			}
+			if got := rule.Named(); got != tt.want {
+				t.Errorf("Named() = %q, want %q", got, tt.want)
+			}
+			reparsed, err := parseRule(rule.Named())
+			if err != nil {
+				t.Fatalf("failed to parse named rule %q: %v", rule.Named(), err)
+			}
+			if reparsed.String() != rule.String() {
+				t.Errorf("named rule parsed as %q, want %q", reparsed.String(), rule.String())
+			}
+		})
+	}
+}
+
+func TestRuleAccessors(t *testing.T) {
+	RegisterCalendar("test-accessors", NewHolidays())
+	rules, err := Parse("!TZ=Europe/Berlin DST=elapsed DAYS=or CAL=test-accessors 22:00-02:30:15) 1-5,5L 1,15,H,L */3 " +
+		"2027,2026-12-24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rules[0]
+
+	if r.Location() == nil || r.Location().String() != "Europe/Berlin" {
+		t.Errorf("Location() = %v, want Europe/Berlin", r.Location())
+	}
+	if r.DST() != DSTElapsed || !r.DaysOr() || !r.Exclusion() || r.Calendar() != "test-accessors" {
+		t.Errorf("DST() = %v, DaysOr() = %v, Exclusion() = %v, Calendar() = %q", r.DST(), r.DaysOr(),
+			r.Exclusion(), r.Calendar())
+	}
+
+	tr := r.TimeRange()
+	if tr.All() || !tr.Overnight() || !tr.HalfOpen() || tr.Start() != 22*time.Hour ||
+		tr.End() != 2*time.Hour+30*time.Minute+15*time.Second {
+		t.Errorf("TimeRange() = %+v", tr)
+	}
+
+	checkInts := func(name string, got, want []int) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Errorf("%s = %v, want %v", name, got, want)
+			return
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("%s = %v, want %v", name, got, want)
+				return
+			}
+		}
+	}
+	checkInts("Dow().Values()", r.Dow().Values(), []int{1, 2, 3, 4, 5})
+	checkInts("Dom().Values()", r.Dom().Values(), []int{1, 15})
+	checkInts("Month().Values()", r.Month().Values(), []int{1, 4, 7, 10})
+	if specs := r.Dow().Specifiers(); len(specs) != 1 || specs[0] != "5L" {
+		t.Errorf("Dow().Specifiers() = %v, want [5L]", specs)
+	}
+	if specs := r.Dom().Specifiers(); len(specs) != 2 || specs[0] != "H" || specs[1] != "L" {
+		t.Errorf("Dom().Specifiers() = %v, want [H L]", specs)
+	}
+
+	dates := r.Dates()
+	if len(dates) != 2 || dates[0].From.Format(dateLayout) != "2026-12-24" || !dates[0].From.Equal(dates[0].To) ||
+		dates[1].From.Format(dateLayout) != "2027-01-01" || dates[1].To.Format(dateLayout) != "2027-12-31" {
+		t.Errorf("Dates() = %v", dates)
+	}
+
+	all, err := Parse("* * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := all[0]
+	if !a.TimeRange().All() || !a.Dow().All() || a.Dow().Values() != nil || a.Dates() != nil || a.Location() != nil ||
+		a.DST() != DSTWall || a.Calendar() != "" || a.Exclusion() {
+		t.Errorf("accessors of %q report restrictions", a)
+	}
+}
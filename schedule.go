@@ -0,0 +1,201 @@
+package cronrange
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"time"
+)
+
+// setOp is a set operation combining schedules
+type setOp int
+
+const (
+	opRules      setOp = iota // no operation, the schedule is defined by its rules
+	opUnion                   // any of the operands is active
+	opIntersect               // all of the operands are active
+	opSubtract                // the first operand is active and the second one is not
+	opComplement              // the operand is not active
+)
+
+// Schedule is a set of times defined by rules or by set operations on other schedules, e.g. the intersection
+// of a maintenance window and low-traffic hours, or business hours without maintenance. Rules without
+// a timezone are evaluated in the location of the checked time, as with Match.
+// Schedules are immutable and safe for concurrent use. The zero value is an empty schedule, never active.
+type Schedule struct {
+	op    setOp
+	rules []Rule     // rules of the schedule for opRules
+	args  []Schedule // operands of the set operation
+}
+
+// NewSchedule makes a schedule active when the rules are, i.e. when any of the rules is active and none
+// of the exclusion rules is
+func NewSchedule(rules []Rule) Schedule {
+	return Schedule{rules: slices.Clone(rules)}
+}
+
+// Union returns a schedule active when the schedule or any of the others is active
+func (s Schedule) Union(others ...Schedule) Schedule {
+	if len(others) == 0 {
+		return s
+	}
+	args := append([]Schedule{s}, others...)
+
+	// a union of schedules without exclusion rules is a schedule with all their rules
+	var rules []Rule
+	for _, a := range args {
+		if !a.positive() {
+			return Schedule{op: opUnion, args: args}
+		}
+		rules = append(rules, a.rules...)
+	}
+	return Schedule{rules: rules}
+}
+
+// Intersect returns a schedule active when the schedule and all of the others are active
+func (s Schedule) Intersect(others ...Schedule) Schedule {
+	if len(others) == 0 {
+		return s
+	}
+	return Schedule{op: opIntersect, args: append([]Schedule{s}, others...)}
+}
+
+// Subtract returns the difference of schedules, active when the schedule is active and the other is not
+func (s Schedule) Subtract(other Schedule) Schedule {
+	if s.op != opRules || !other.positive() {
+		return Schedule{op: opSubtract, args: []Schedule{s, other}}
+	}
+
+	// rules of the other schedule become exclusion rules
+	rules := slices.Clone(s.rules)
+	for _, r := range other.rules {
+		r.exclude = true
+		rules = append(rules, r)
+	}
+	return Schedule{rules: rules}
+}
+
+// Complement returns a schedule active when the schedule is not
+func (s Schedule) Complement() Schedule {
+	switch {
+	case s.op == opComplement:
+		return s.args[0]
+	case s.positive():
+		// always active except for the rules of the schedule
+		return NewSchedule([]Rule{NewRule().rule}).Subtract(s)
+	case s.op == opRules && slices.ContainsFunc(s.rules, always):
+		// always active except for the exclusion rules, so the complement is active when any of them is
+		var rules []Rule
+		for _, r := range s.rules {
+			if r.exclude {
+				r.exclude = false
+				rules = append(rules, r)
+			}
+		}
+		return Schedule{rules: rules}
+	}
+	return Schedule{op: opComplement, args: []Schedule{s}}
+}
+
+// Rules returns rules matching the same times as the schedule. The second value is false if the schedule
+// is a result of set operations which can't be expressed by rules.
+func (s Schedule) Rules() ([]Rule, bool) {
+	if s.op != opRules {
+		return nil, false
+	}
+	return slices.Clone(s.rules), true
+}
+
+// Match checks if the schedule is active at the given time
+func (s Schedule) Match(t time.Time) bool {
+	switch s.op {
+	case opUnion:
+		return slices.ContainsFunc(s.args, func(a Schedule) bool { return a.Match(t) })
+	case opIntersect:
+		return !slices.ContainsFunc(s.args, func(a Schedule) bool { return !a.Match(t) })
+	case opSubtract:
+		return s.args[0].Match(t) && !s.args[1].Match(t)
+	case opComplement:
+		return !s.args[0].Match(t)
+	}
+	return Match(s.rules, t)
+}
+
+// Intervals returns an iterator over merged, non-overlapping intervals when the schedule is active
+// within [from, to), the same way as the Intervals function does for rules
+func (s Schedule) Intervals(from, to time.Time) iter.Seq[Interval] {
+	return intervalsOf(s.spans(from, to), from, to)
+}
+
+// NextStart returns the moment strictly after the given time when the schedule becomes active,
+// the same way as the NextStart function does for rules
+func (s Schedule) NextStart(after time.Time) (time.Time, bool) {
+	return nextStart(s.spans(after, after.AddDate(searchHorizonYears, 0, 0)), after)
+}
+
+// NextEnd returns the moment strictly after the given time when the schedule stops being active,
+// the same way as the NextEnd function does for rules
+func (s Schedule) NextEnd(after time.Time) (time.Time, bool) {
+	horizon := after.AddDate(searchHorizonYears, 0, 0)
+	return nextEnd(s.spans(after, horizon), after, horizon)
+}
+
+// String returns the schedule as an expression of rules if possible. Results of other set operations
+// are written with rules in braces and operators | (union), & (intersection), - (difference)
+// and not (complement), e.g. "{09:00-17:00 1-5 * *} & not {12:00-13:00 * * *}".
+func (s Schedule) String() string {
+	if s.op == opRules {
+		return Rules(s.rules).String()
+	}
+	if s.op == opComplement {
+		return "not " + s.args[0].operand()
+	}
+
+	sep := map[setOp]string{opUnion: " | ", opIntersect: " & ", opSubtract: " - "}[s.op]
+	parts := make([]string, 0, len(s.args))
+	for _, a := range s.args {
+		parts = append(parts, a.operand())
+	}
+	return strings.Join(parts, sep)
+}
+
+// operand returns the string representation of the schedule as an operand of a set operation
+func (s Schedule) operand() string {
+	if s.op == opRules {
+		return "{" + s.String() + "}"
+	}
+	return "(" + s.String() + ")"
+}
+
+// positive checks if the schedule is defined by rules without exclusion rules
+func (s Schedule) positive() bool {
+	return s.op == opRules && !slices.ContainsFunc(s.rules, func(r Rule) bool { return r.exclude })
+}
+
+// spans returns merged, non-overlapping spans when the schedule is active, clipped to [from, to)
+// with from rounded down and to rounded up to a whole second
+func (s Schedule) spans(from, to time.Time) iter.Seq[span] {
+	switch s.op {
+	case opUnion, opIntersect:
+		combine := mergeSpans
+		if s.op == opIntersect {
+			combine = intersectSpans
+		}
+		res := s.args[0].spans(from, to)
+		for _, a := range s.args[1:] {
+			res = combine(res, a.spans(from, to))
+		}
+		return res
+	case opSubtract:
+		return subtractSpans(s.args[0].spans(from, to), s.args[1].spans(from, to))
+	case opComplement:
+		lo, hi := secondBounds(from, to)
+		return subtractSpans(slices.Values([]span{{start: lo, end: hi}}), s.args[0].spans(from, to))
+	}
+	return activeSpans(s.rules, from, to)
+}
+
+// always checks if the rule is a positive rule active at any time
+func always(r Rule) bool {
+	return !r.exclude && r.timeRange.all && everyDay(r)
+}
@@ -0,0 +1,202 @@
+package cronrange
+
+import (
+	"testing"
+	"time"
+)
+
+func mustSchedule(t *testing.T, expr string) Schedule {
+	t.Helper()
+	rules, err := Parse(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewSchedule(rules)
+}
+
+func TestScheduleString(t *testing.T) {
+	business := mustSchedule(t, "09:00-17:00 1-5 * *")
+	lunch := mustSchedule(t, "12:00-13:00 * * *")
+	nights := mustSchedule(t, "22:00-06:00 * * *; !* 0 * *")
+
+	tests := []struct {
+		name      string
+		schedule  Schedule
+		want      string
+		wantRules bool
+	}{
+		{name: "empty", schedule: Schedule{}, want: "", wantRules: true},
+		{name: "rules", schedule: business, want: "09:00-17:00 1-5 * *", wantRules: true},
+		{
+			name:      "union of rules",
+			schedule:  business.Union(lunch),
+			want:      "09:00-17:00 1-5 * *; 12:00-13:00 * * *",
+			wantRules: true,
+		},
+		{
+			name:     "union with exclusions",
+			schedule: business.Union(nights),
+			want:     "{09:00-17:00 1-5 * *} | {22:00-06:00 * * *; !* 0 * *}",
+		},
+		{name: "union of one", schedule: nights.Union(), want: "22:00-06:00 * * *; !* 0 * *", wantRules: true},
+		{
+			name:     "intersection",
+			schedule: business.Intersect(lunch),
+			want:     "{09:00-17:00 1-5 * *} & {12:00-13:00 * * *}",
+		},
+		{
+			name:      "difference",
+			schedule:  business.Subtract(lunch),
+			want:      "09:00-17:00 1-5 * *; !12:00-13:00 * * *",
+			wantRules: true,
+		},
+		{
+			name:     "difference with exclusions",
+			schedule: business.Subtract(nights),
+			want:     "{09:00-17:00 1-5 * *} - {22:00-06:00 * * *; !* 0 * *}",
+		},
+		{name: "complement", schedule: lunch.Complement(), want: "* * * *; !12:00-13:00 * * *", wantRules: true},
+		{
+			name:      "double complement",
+			schedule:  lunch.Complement().Complement(),
+			want:      "12:00-13:00 * * *",
+			wantRules: true,
+		},
+		{name: "complement with exclusions", schedule: nights.Complement(), want: "not {22:00-06:00 * * *; !* 0 * *}"},
+		{
+			name:     "complement of operation",
+			schedule: business.Intersect(lunch).Complement(),
+			want:     "not ({09:00-17:00 1-5 * *} & {12:00-13:00 * * *})",
+		},
+		{
+			name:     "nested",
+			schedule: business.Intersect(nights.Union(lunch)).Subtract(lunch),
+			want:     "({09:00-17:00 1-5 * *} & ({22:00-06:00 * * *; !* 0 * *} | {12:00-13:00 * * *})) - {12:00-13:00 * * *}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			rules, ok := tt.schedule.Rules()
+			if ok != tt.wantRules {
+				t.Fatalf("Rules() ok = %v, want %v", ok, tt.wantRules)
+			}
+			if ok && Rules(rules).String() != tt.want {
+				t.Errorf("Rules() = %q, want %q", Rules(rules).String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleMatch(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	business := mustSchedule(t, "09:00-17:00 1-5 * *")
+	maintenance := mustSchedule(t, "TZ=UTC 01:00-03:00 * * *; 14:00-15:30) 3 * *")
+	lowTraffic := mustSchedule(t, "22:00-07:00 * * *; !* 6 * *")
+	weekend := mustSchedule(t, "* 0,6 * *")
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		want     func(tm time.Time) bool
+	}{
+		{
+			name:     "maintenance in low traffic",
+			schedule: maintenance.Intersect(lowTraffic),
+			want:     func(tm time.Time) bool { return maintenance.Match(tm) && lowTraffic.Match(tm) },
+		},
+		{
+			name:     "business without maintenance",
+			schedule: business.Subtract(maintenance),
+			want:     func(tm time.Time) bool { return business.Match(tm) && !maintenance.Match(tm) },
+		},
+		{
+			name:     "union",
+			schedule: business.Union(lowTraffic, weekend),
+			want: func(tm time.Time) bool {
+				return business.Match(tm) || lowTraffic.Match(tm) || weekend.Match(tm)
+			},
+		},
+		{
+			name:     "difference of exclusions",
+			schedule: weekend.Subtract(lowTraffic),
+			want:     func(tm time.Time) bool { return weekend.Match(tm) && !lowTraffic.Match(tm) },
+		},
+		{
+			name:     "complement",
+			schedule: lowTraffic.Complement(),
+			want:     func(tm time.Time) bool { return !lowTraffic.Match(tm) },
+		},
+		{
+			name:     "complement of rules",
+			schedule: business.Complement().Intersect(weekend.Complement()),
+			want:     func(tm time.Time) bool { return !business.Match(tm) && !weekend.Match(tm) },
+		},
+		{
+			name:     "nested",
+			schedule: business.Union(weekend).Intersect(lowTraffic.Union(maintenance)).Complement(),
+			want: func(tm time.Time) bool {
+				return !((business.Match(tm) || weekend.Match(tm)) && (lowTraffic.Match(tm) || maintenance.Match(tm)))
+			},
+		},
+		{name: "empty", schedule: Schedule{}, want: func(time.Time) bool { return false }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// two weeks around the spring clock change
+			from := time.Date(2024, 3, 3, 0, 0, 30, 0, ny)
+			to := from.AddDate(0, 0, 14)
+			var intervals []Interval
+			for iv := range tt.schedule.Intervals(from, to) {
+				if len(intervals) > 0 && !intervals[len(intervals)-1].End.Before(iv.Start) {
+					t.Fatalf("intervals %v and %v are not merged", intervals[len(intervals)-1], iv)
+				}
+				intervals = append(intervals, iv)
+			}
+
+			for tm := from; tm.Before(to); tm = tm.Add(7 * time.Minute) {
+				want := tt.want(tm)
+				if got := tt.schedule.Match(tm); got != want {
+					t.Fatalf("Match(%v) = %v, want %v", tm, got, want)
+				}
+				inInterval := false
+				for _, iv := range intervals {
+					inInterval = inInterval || iv.Contains(tm)
+				}
+				if inInterval != want {
+					t.Fatalf("at %v in interval = %v, want %v", tm, inInterval, want)
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	business := mustSchedule(t, "09:00-17:00 1-5 * *")
+	lunch := mustSchedule(t, "12:00-13:00) * * *")
+	meetings := business.Subtract(lunch).Intersect(mustSchedule(t, "* 1,3 * *"))
+
+	after := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC) // Tuesday
+	start, ok := meetings.NextStart(after)
+	if !ok || !start.Equal(time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("NextStart() = %v, %v, want 2024-01-03 09:00", start, ok)
+	}
+	end, ok := meetings.NextEnd(after)
+	if !ok || !end.Equal(time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("NextEnd() = %v, %v, want 2024-01-03 12:00", end, ok)
+	}
+
+	if _, ok := meetings.Intersect(mustSchedule(t, "* 0 * *")).NextStart(after); ok {
+		t.Error("NextStart() of a never active schedule = true, want false")
+	}
+	if _, ok := lunch.Complement().Union(business.Complement(), lunch).NextEnd(after); ok {
+		t.Error("NextEnd() of an always active schedule = true, want false")
+	}
+}
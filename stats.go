@@ -0,0 +1,49 @@
+package cronrange
+
+import (
+	"time"
+)
+
+const week = 7 * 24 * time.Hour
+
+// Stats summarizes when rules are active within a period
+type Stats struct {
+	Period   time.Duration // length of the period
+	Active   time.Duration // total time the rules are active
+	Windows  int           // number of contiguous windows when the rules are active
+	Longest  Interval      // the longest window, the earliest one of equal windows, zero if there are no windows
+	Shortest Interval      // the shortest window, the earliest one of equal windows, zero if there are no windows
+}
+
+// Summarize computes statistics of the rules within [from, to), e.g. the total active time or
+// the longest window. Windows are merged and clipped to the period as by Intervals, so windows
+// crossing its boundaries count partially. Rules without a timezone are evaluated in the location of from.
+func Summarize(rules []Rule, from, to time.Time) Stats {
+	res := Stats{Period: max(to.Sub(from), 0)}
+	for iv := range Intervals(rules, from, to) {
+		d := iv.Duration()
+		res.Active += d
+		if res.Windows == 0 || d > res.Longest.Duration() {
+			res.Longest = iv
+		}
+		if res.Windows == 0 || d < res.Shortest.Duration() {
+			res.Shortest = iv
+		}
+		res.Windows++
+	}
+	return res
+}
+
+// Coverage returns the fraction of the period the rules are active, from 0 to 1
+func (s Stats) Coverage() float64 {
+	if s.Period <= 0 {
+		return 0
+	}
+	return float64(s.Active) / float64(s.Period)
+}
+
+// PerWeek returns the average active time per week of the period, e.g. 40h for business hours
+// of 09:00-17:00 on weekdays
+func (s Stats) PerWeek() time.Duration {
+	return time.Duration(s.Coverage() * float64(week))
+}
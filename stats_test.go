@@ -0,0 +1,119 @@
+package cronrange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	saturday := monday.AddDate(0, 0, 5)
+
+	tests := []struct {
+		name     string
+		expr     string
+		from, to time.Time
+		want     Stats
+		coverage float64
+		perWeek  time.Duration
+	}{
+		{
+			name: "business hours",
+			expr: "09:00-17:00) 1-5 * *",
+			from: monday,
+			to:   monday.AddDate(0, 0, 7),
+			want: Stats{
+				Period:   week,
+				Active:   40 * time.Hour,
+				Windows:  5,
+				Longest:  Interval{Start: monday.Add(9 * time.Hour), End: monday.Add(17 * time.Hour)},
+				Shortest: Interval{Start: monday.Add(9 * time.Hour), End: monday.Add(17 * time.Hour)},
+			},
+			coverage: 40.0 / 168,
+			perWeek:  40 * time.Hour,
+		},
+		{
+			name: "windows of different length over two weeks",
+			expr: "09:00-17:00) 1-5 * *; 10:00-12:00) 6 * *; 22:00-02:00) 5 * *",
+			from: monday,
+			to:   monday.AddDate(0, 0, 14),
+			want: Stats{
+				Period:   2 * week,
+				Active:   2 * (40 + 2 + 4) * time.Hour,
+				Windows:  14,
+				Longest:  Interval{Start: monday.Add(9 * time.Hour), End: monday.Add(17 * time.Hour)},
+				Shortest: Interval{Start: saturday.Add(10 * time.Hour), End: saturday.Add(12 * time.Hour)},
+			},
+			coverage: 92.0 / 336,
+			perWeek:  46 * time.Hour,
+		},
+		{
+			name: "windows clipped to the period",
+			expr: "22:00-06:00) * * *",
+			from: monday,
+			to:   monday.Add(24 * time.Hour),
+			want: Stats{
+				Period:   24 * time.Hour,
+				Active:   8 * time.Hour,
+				Windows:  2,
+				Longest:  Interval{Start: monday, End: monday.Add(6 * time.Hour)},
+				Shortest: Interval{Start: monday.Add(22 * time.Hour), End: monday.Add(24 * time.Hour)},
+			},
+			coverage: 1.0 / 3,
+			perWeek:  56 * time.Hour,
+		},
+		{
+			name: "week with clock change",
+			expr: "* * * *; !02:00-02:59:59 * * *",
+			from: time.Date(2024, 3, 10, 0, 0, 0, 0, ny),
+			to:   time.Date(2024, 3, 17, 0, 0, 0, 0, ny),
+			want: Stats{
+				Period:   167 * time.Hour,
+				Active:   161 * time.Hour, // 02:00 doesn't happen on the day of the change
+				Windows:  7,
+				Longest:  Interval{Start: time.Date(2024, 3, 10, 0, 0, 0, 0, ny), End: time.Date(2024, 3, 11, 2, 0, 0, 0, ny)},
+				Shortest: Interval{Start: time.Date(2024, 3, 16, 3, 0, 0, 0, ny), End: time.Date(2024, 3, 17, 0, 0, 0, 0, ny)},
+			},
+			coverage: 161.0 / 167,
+			perWeek:  week * 161 / 167,
+		},
+		{
+			name: "never active",
+			expr: "* * 30 2",
+			from: monday,
+			to:   monday.AddDate(1, 0, 0),
+			want: Stats{Period: 366 * 24 * time.Hour},
+		},
+		{name: "empty period", expr: "* * * *", from: monday, to: monday, want: Stats{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := Summarize(rules, tt.from, tt.to)
+			if got.Period != tt.want.Period || got.Active != tt.want.Active || got.Windows != tt.want.Windows {
+				t.Errorf("Summarize() = %v, %v, %d windows, want %v, %v, %d windows",
+					got.Period, got.Active, got.Windows, tt.want.Period, tt.want.Active, tt.want.Windows)
+			}
+			if !got.Longest.Start.Equal(tt.want.Longest.Start) || !got.Longest.End.Equal(tt.want.Longest.End) {
+				t.Errorf("Longest = %v, want %v", got.Longest, tt.want.Longest)
+			}
+			if !got.Shortest.Start.Equal(tt.want.Shortest.Start) || !got.Shortest.End.Equal(tt.want.Shortest.End) {
+				t.Errorf("Shortest = %v, want %v", got.Shortest, tt.want.Shortest)
+			}
+			if c := got.Coverage(); c < tt.coverage-1e-9 || c > tt.coverage+1e-9 {
+				t.Errorf("Coverage() = %v, want %v", c, tt.coverage)
+			}
+			if d := got.PerWeek(); d < tt.perWeek-time.Microsecond || d > tt.perWeek+time.Microsecond {
+				t.Errorf("PerWeek() = %v, want %v", d, tt.perWeek)
+			}
+		})
+	}
+}
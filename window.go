@@ -0,0 +1,367 @@
+package cronrange
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+	"time"
+)
+
+const (
+	secondsPerDay = 24 * 60 * 60
+
+	// maxZoneOffset bounds the difference between wall clock and UTC in any location, in seconds
+	maxZoneOffset = 24 * 60 * 60
+
+	// searchHorizonYears limits how far NextStart and NextEnd look ahead. Calendar fields repeat within
+	// the 400-year Gregorian cycle, so rules never active in this period will never be active at all.
+	searchHorizonYears = 400
+)
+
+// Interval is a period of time when rules are active, Start is inclusive and End is exclusive
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the interval
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// Contains checks if t falls within the interval
+func (i Interval) Contains(t time.Time) bool {
+	return !t.Before(i.Start) && t.Before(i.End)
+}
+
+// String returns the string representation of an Interval
+func (i Interval) String() string {
+	return fmt.Sprintf("[%s, %s)", i.Start.Format(time.RFC3339), i.End.Format(time.RFC3339))
+}
+
+// intervalsOf converts spans to intervals in the location of from, clipped to [from, to)
+func intervalsOf(spans iter.Seq[span], from, to time.Time) iter.Seq[Interval] {
+	return func(yield func(Interval) bool) {
+		loc := from.Location()
+		for s := range spans {
+			interval := Interval{Start: time.Unix(s.start, 0).In(loc), End: time.Unix(s.end, 0).In(loc)}
+			if interval.Start.Before(from) {
+				interval.Start = from
+			}
+			if interval.End.After(to) {
+				interval.End = to.In(loc)
+			}
+			if !interval.Start.Before(interval.End) {
+				continue
+			}
+			if !yield(interval) {
+				return
+			}
+		}
+	}
+}
+
+// nextStart returns the first start of spans strictly after the given time
+func nextStart(spans iter.Seq[span], after time.Time) (time.Time, bool) {
+	for s := range spans {
+		if start := time.Unix(s.start, 0); start.After(after) {
+			return start.In(after.Location()), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextEnd returns the first end of spans strictly after the given time and before the horizon
+func nextEnd(spans iter.Seq[span], after, horizon time.Time) (time.Time, bool) {
+	for s := range spans {
+		end := time.Unix(s.end, 0)
+		if !end.Before(horizon) {
+			break
+		}
+		if end.After(after) {
+			return end.In(after.Location()), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// span is a period of absolute time in unix seconds, start inclusive and end exclusive
+type span struct {
+	start, end int64
+}
+
+// wallSpans appends to buf the spans of absolute time during which the wall clock in loc shows a reading
+// within [lo, hi). Readings are passed as unix seconds of the same wall-clock reading in UTC.
+// Readings skipped by a forward clock change produce no time, repeated readings produce two spans.
+func wallSpans(buf []span, lo, hi int64, loc *time.Location) []span {
+	if lo >= hi {
+		return buf
+	}
+
+	t := time.Unix(lo-maxZoneOffset, 0).In(loc)
+	for {
+		_, offset := t.Zone()
+		zoneStart, zoneEnd := t.ZoneBounds()
+		if !zoneEnd.IsZero() && !zoneEnd.After(t) {
+			// beyond the transition table of a location ZoneBounds may report an end before t, e.g. on the last
+			// day of leap years, so walk such periods in small steps with the offset reported by Zone
+			zoneStart, zoneEnd = t, t.Add(time.Hour)
+		}
+
+		// the part of [lo, hi) covered by the zone period around t
+		start, end := lo-int64(offset), hi-int64(offset)
+		if !zoneStart.IsZero() {
+			start = max(start, zoneStart.Unix())
+		}
+		if !zoneEnd.IsZero() {
+			end = min(end, zoneEnd.Unix())
+		}
+		if start < end {
+			buf = appendSpan(buf, span{start: start, end: end})
+		}
+
+		if zoneEnd.IsZero() || zoneEnd.Unix() >= hi+maxZoneOffset {
+			return buf
+		}
+		t = zoneEnd
+	}
+}
+
+// appendSpan appends s to buf, extending the last span instead if s continues it
+func appendSpan(buf []span, s span) []span {
+	if n := len(buf); n > 0 && buf[n-1].end == s.start {
+		buf[n-1].end = s.end
+		return buf
+	}
+	return append(buf, s)
+}
+
+// ruleCursor walks spans of a single rule day by day in chronological order
+type ruleCursor struct {
+	rule Rule
+	loc  *time.Location
+	day  int64 // next calendar day to expand, days since 1970-01-01
+	last int64 // last calendar day to expand, days since 1970-01-01
+	buf  []span
+}
+
+// next returns the next span of the rule or false if there are no more days to expand
+func (c *ruleCursor) next() (span, bool) {
+	for len(c.buf) == 0 {
+		if c.day > c.last {
+			return span{}, false
+		}
+		day := civilFromDays(c.day)
+		if !c.rule.month.matches(int(day.month)) {
+			// nothing to expand in this month, jump to the first day of the next one
+			c.day += int64(daysIn(day.year, day.month) - day.day + 1)
+			continue
+		}
+		c.buf = c.rule.windows(day, c.loc, c.buf[:0])
+		slices.SortFunc(c.buf, func(a, b span) int { return cmp.Compare(a.start, b.start) })
+		c.day++
+	}
+
+	s := c.buf[0]
+	c.buf = c.buf[1:]
+	return s, true
+}
+
+// activeSpans returns merged, non-overlapping spans when any of the positive rules is active and none
+// of the exclusion rules is, clipped to [from, to) with from rounded down and to rounded up to a whole
+// second. Rules without a timezone are evaluated in the location of from.
+func activeSpans(rules []Rule, from, to time.Time) iter.Seq[span] {
+	var include, exclude []Rule
+	for _, r := range rules {
+		if r.exclude {
+			exclude = append(exclude, r)
+			continue
+		}
+		include = append(include, r)
+	}
+
+	if len(exclude) == 0 {
+		return unionSpans(include, from, to)
+	}
+	return subtractSpans(unionSpans(include, from, to), unionSpans(exclude, from, to))
+}
+
+// unionSpans returns merged, non-overlapping spans when any of the rules is active, clipped to [from, to)
+// with from rounded down and to rounded up to a whole second. The exclusion flag of the rules is ignored.
+func unionSpans(rules []Rule, from, to time.Time) iter.Seq[span] {
+	return func(yield func(span) bool) {
+		lo, hi := secondBounds(from, to)
+
+		cursors := make([]*ruleCursor, len(rules))
+		heads := make([]span, len(rules))
+		alive := make([]bool, len(rules))
+		for i, r := range rules {
+			cursors[i] = newRuleCursor(r, from, to)
+			heads[i], alive[i] = cursors[i].next()
+		}
+
+		var cur span
+		pending := false
+		for {
+			// pick the earliest head among all rules
+			idx := -1
+			for i := range cursors {
+				if alive[i] && (idx < 0 || heads[i].start < heads[idx].start) {
+					idx = i
+				}
+			}
+			if idx < 0 {
+				break
+			}
+			s := heads[idx]
+			heads[idx], alive[idx] = cursors[idx].next()
+
+			s.start, s.end = max(s.start, lo), min(s.end, hi)
+			if s.start >= s.end {
+				if s.start >= hi {
+					alive[idx] = false // spans of this rule are sorted, the rest is out of range too
+				}
+				continue
+			}
+			if pending && s.start <= cur.end {
+				cur.end = max(cur.end, s.end)
+				continue
+			}
+			if pending && !yield(cur) {
+				return
+			}
+			cur, pending = s, true
+		}
+
+		if pending {
+			yield(cur)
+		}
+	}
+}
+
+// secondBounds returns [from, to) in unix seconds, with from rounded down and to rounded up to a whole second
+func secondBounds(from, to time.Time) (lo, hi int64) {
+	lo, hi = from.Unix(), to.Unix()
+	if to.Nanosecond() > 0 {
+		hi++
+	}
+	return lo, hi
+}
+
+// subtractSpans returns spans of a with all spans of b cut out. Both must be sorted and non-overlapping.
+func subtractSpans(a, b iter.Seq[span]) iter.Seq[span] {
+	return func(yield func(span) bool) {
+		next, stop := iter.Pull(b)
+		defer stop()
+
+		cut, ok := next()
+		for s := range a {
+			for ok && cut.end <= s.start {
+				cut, ok = next() // skip cuts before the span
+			}
+			for ok && cut.start < s.end {
+				if cut.start > s.start && !yield(span{start: s.start, end: cut.start}) {
+					return
+				}
+				if cut.end >= s.end {
+					s.start = s.end // the rest of the span is cut out, the cut may affect the next span too
+					break
+				}
+				s.start = cut.end
+				cut, ok = next()
+			}
+			if s.start < s.end && !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// mergeSpans returns spans when either a or b is active, with overlapping and adjacent spans merged.
+// Both must be sorted and non-overlapping.
+func mergeSpans(a, b iter.Seq[span]) iter.Seq[span] {
+	return func(yield func(span) bool) {
+		nextA, stopA := iter.Pull(a)
+		defer stopA()
+		nextB, stopB := iter.Pull(b)
+		defer stopB()
+
+		headA, okA := nextA()
+		headB, okB := nextB()
+		var cur span
+		pending := false
+		for okA || okB {
+			var s span
+			if okA && (!okB || headA.start <= headB.start) {
+				s = headA
+				headA, okA = nextA()
+			} else {
+				s = headB
+				headB, okB = nextB()
+			}
+			if pending && s.start <= cur.end {
+				cur.end = max(cur.end, s.end)
+				continue
+			}
+			if pending && !yield(cur) {
+				return
+			}
+			cur, pending = s, true
+		}
+		if pending {
+			yield(cur)
+		}
+	}
+}
+
+// intersectSpans returns spans when both a and b are active. Both must be sorted and non-overlapping.
+func intersectSpans(a, b iter.Seq[span]) iter.Seq[span] {
+	return func(yield func(span) bool) {
+		nextA, stopA := iter.Pull(a)
+		defer stopA()
+		nextB, stopB := iter.Pull(b)
+		defer stopB()
+
+		headA, okA := nextA()
+		headB, okB := nextB()
+		for okA && okB {
+			s := span{start: max(headA.start, headB.start), end: min(headA.end, headB.end)}
+			if s.start < s.end && !yield(s) {
+				return
+			}
+			// the span ending first can't intersect anything else
+			if headA.end <= headB.end {
+				headA, okA = nextA()
+			} else {
+				headB, okB = nextB()
+			}
+		}
+	}
+}
+
+// newRuleCursor makes a cursor expanding all days of the rule which can produce spans within [from, to).
+// Rules without a timezone are evaluated in the location of from.
+func newRuleCursor(r Rule, from, to time.Time) *ruleCursor {
+	loc := from.Location()
+	if r.loc != nil {
+		loc = r.loc
+	}
+	c := &ruleCursor{
+		rule: r,
+		loc:  loc,
+		day:  dayNumber(from, loc) - 1, // previous day may spill over midnight
+		last: dayNumber(to, loc) + 1,
+	}
+
+	if len(r.dates) > 0 {
+		// no need to expand days outside of the absolute dates of the rule, which are sorted by start
+		c.day = max(c.day, r.dates[0].from)
+		last := r.dates[0].to
+		for _, dr := range r.dates[1:] {
+			last = max(last, dr.to)
+		}
+		c.last = min(c.last, last)
+	}
+	return c
+}
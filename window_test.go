@@ -0,0 +1,275 @@
+package cronrange
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWallSpans(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wall := func(y int, m time.Month, d, h, mi int) int64 {
+		return time.Date(y, m, d, h, mi, 0, 0, time.UTC).Unix()
+	}
+	at := func(y int, m time.Month, d, h, mi int, loc *time.Location) int64 {
+		return time.Date(y, m, d, h, mi, 0, 0, loc).Unix()
+	}
+
+	tests := []struct {
+		name   string
+		lo, hi int64
+		loc    *time.Location
+		want   []span
+	}{
+		{
+			name: "utc",
+			lo:   wall(2024, 1, 1, 9, 0),
+			hi:   wall(2024, 1, 1, 17, 0),
+			loc:  time.UTC,
+			want: []span{{at(2024, 1, 1, 9, 0, time.UTC), at(2024, 1, 1, 17, 0, time.UTC)}},
+		},
+		{
+			name: "regular day",
+			lo:   wall(2024, 1, 1, 9, 0),
+			hi:   wall(2024, 1, 1, 17, 0),
+			loc:  ny,
+			want: []span{{at(2024, 1, 1, 9, 0, ny), at(2024, 1, 1, 17, 0, ny)}},
+		},
+		{
+			name: "skipped hour",
+			lo:   wall(2024, 3, 10, 2, 0),
+			hi:   wall(2024, 3, 10, 3, 0),
+			loc:  ny,
+			want: nil,
+		},
+		{
+			name: "range across skipped hour",
+			lo:   wall(2024, 3, 10, 1, 0),
+			hi:   wall(2024, 3, 10, 4, 0),
+			loc:  ny,
+			want: []span{{at(2024, 3, 10, 1, 0, ny), at(2024, 3, 10, 1, 0, ny) + 2*3600}},
+		},
+		{
+			name: "repeated hour",
+			lo:   wall(2024, 11, 3, 1, 0),
+			hi:   wall(2024, 11, 3, 1, 30),
+			loc:  ny,
+			want: []span{
+				{at(2024, 11, 3, 5, 0, time.UTC), at(2024, 11, 3, 5, 30, time.UTC)},
+				{at(2024, 11, 3, 6, 0, time.UTC), at(2024, 11, 3, 6, 30, time.UTC)},
+			},
+		},
+		{
+			name: "range across repeated hour",
+			lo:   wall(2024, 11, 3, 0, 0),
+			hi:   wall(2024, 11, 3, 3, 0),
+			loc:  ny,
+			want: []span{{at(2024, 11, 3, 4, 0, time.UTC), at(2024, 11, 3, 8, 0, time.UTC)}},
+		},
+		{
+			name: "last day of leap year beyond transition table",
+			lo:   wall(2040, 12, 31, 9, 0),
+			hi:   wall(2040, 12, 31, 17, 0),
+			loc:  ny,
+			want: []span{{at(2040, 12, 31, 9, 0, ny), at(2040, 12, 31, 17, 0, ny)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wallSpans(nil, tt.lo, tt.hi, tt.loc)
+			if len(got) != len(tt.want) {
+				t.Fatalf("wallSpans() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("wallSpans()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestActiveSpansAgreeWithMatch checks spans against Match for every minute around DST transitions
+func TestActiveSpansAgreeWithMatch(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exprs := []string{
+		"09:00-17:00 1-5 * *",
+		"23:00-02:00 * * *",
+		"01:30-02:30 * * *",
+		"00:30:15-01:15:45 0 * *",
+		"* 0,6 * *; 12:00-13:00 * 1,15 *",
+		"22:00-03:00 0 3,10 3,11",
+		"TZ=Europe/London 00:30-01:30 0 * *",
+		"TZ=Asia/Kolkata 23:00-01:00 * * *; TZ=America/New_York 09:00-10:00 * * *",
+		"* * * *; !01:00-02:00 * * *; !* 0 * *",
+		"22:00-06:00 * * *; !00:00-00:30) * * *; !05:00-05:10 1-5 * *",
+	}
+	periods := []time.Time{
+		time.Date(2024, 3, 8, 0, 0, 0, 0, ny),
+		time.Date(2024, 11, 1, 0, 0, 0, 0, ny),
+		time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, expr := range exprs {
+		rules, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", expr, err)
+		}
+		for _, from := range periods {
+			to := from.Add(4 * 24 * time.Hour)
+			var spans []span
+			for s := range activeSpans(rules, from, to) {
+				spans = append(spans, s)
+			}
+			for i := 1; i < len(spans); i++ {
+				if spans[i].start <= spans[i-1].end {
+					t.Fatalf("%q: spans %v and %v are not disjoint", expr, spans[i-1], spans[i])
+				}
+			}
+
+			for tm := from; tm.Before(to); tm = tm.Add(time.Minute) {
+				inSpan := false
+				for _, s := range spans {
+					if tm.Unix() >= s.start && tm.Unix() < s.end {
+						inSpan = true
+						break
+					}
+				}
+				if got := Match(rules, tm); got != inSpan {
+					t.Fatalf("%q at %v: Match() = %v, in span = %v", expr, tm, got, inSpan)
+				}
+			}
+		}
+	}
+}
+
+func TestInterval(t *testing.T) {
+	iv := Interval{
+		Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC),
+	}
+	if got := iv.Duration(); got != 8*time.Hour {
+		t.Errorf("Duration() = %v, want 8h", got)
+	}
+	if !iv.Contains(iv.Start) {
+		t.Error("Contains(start) = false, want true")
+	}
+	if iv.Contains(iv.End) {
+		t.Error("Contains(end) = true, want false")
+	}
+	if got, want := iv.String(), "[2024-01-01T09:00:00Z, 2024-01-01T17:00:00Z)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestSubtractSpans(t *testing.T) {
+	seq := func(spans ...span) func(func(span) bool) {
+		return func(yield func(span) bool) {
+			for _, s := range spans {
+				if !yield(s) {
+					return
+				}
+			}
+		}
+	}
+
+	tests := []struct {
+		name string
+		a, b []span
+		want []span
+	}{
+		{name: "nothing to subtract", a: []span{{0, 10}, {20, 30}}, want: []span{{0, 10}, {20, 30}}},
+		{name: "cut in the middle", a: []span{{0, 10}}, b: []span{{3, 5}}, want: []span{{0, 3}, {5, 10}}},
+		{name: "cut at both ends", a: []span{{0, 10}}, b: []span{{-5, 2}, {8, 15}}, want: []span{{2, 8}}},
+		{name: "cut whole span", a: []span{{0, 10}, {20, 30}}, b: []span{{0, 10}}, want: []span{{20, 30}}},
+		{
+			name: "one cut over several spans",
+			a:    []span{{0, 10}, {20, 30}, {40, 50}},
+			b:    []span{{5, 45}},
+			want: []span{{0, 5}, {45, 50}},
+		},
+		{
+			name: "several cuts in one span",
+			a:    []span{{0, 100}},
+			b:    []span{{10, 20}, {30, 40}, {90, 100}},
+			want: []span{{0, 10}, {20, 30}, {40, 90}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []span
+			for s := range subtractSpans(seq(tt.a...), seq(tt.b...)) {
+				got = append(got, s)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("subtractSpans() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("subtractSpans() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestMergeAndIntersectSpans(t *testing.T) {
+	tests := []struct {
+		name           string
+		a, b           []span
+		merged, common []span
+	}{
+		{name: "empty", a: []span{{0, 10}}, merged: []span{{0, 10}}},
+		{name: "disjoint", a: []span{{0, 10}, {40, 50}}, b: []span{{20, 30}}, merged: []span{{0, 10}, {20, 30}, {40, 50}}},
+		{name: "adjacent", a: []span{{0, 10}}, b: []span{{10, 20}}, merged: []span{{0, 20}}},
+		{
+			name:   "overlapping",
+			a:      []span{{0, 10}, {20, 30}},
+			b:      []span{{5, 25}},
+			merged: []span{{0, 30}},
+			common: []span{{5, 10}, {20, 25}},
+		},
+		{
+			name:   "nested",
+			a:      []span{{0, 100}},
+			b:      []span{{10, 20}, {30, 40}},
+			merged: []span{{0, 100}},
+			common: []span{{10, 20}, {30, 40}},
+		},
+		{
+			name:   "same",
+			a:      []span{{0, 10}, {20, 30}},
+			b:      []span{{0, 10}, {20, 30}},
+			merged: []span{{0, 10}, {20, 30}},
+			common: []span{{0, 10}, {20, 30}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, swap := range []bool{false, true} {
+				a, b := tt.a, tt.b
+				if swap {
+					a, b = b, a
+				}
+				if got := slices.Collect(mergeSpans(slices.Values(a), slices.Values(b))); !slices.Equal(got, tt.merged) {
+					t.Errorf("mergeSpans(%v, %v) = %v, want %v", a, b, got, tt.merged)
+				}
+				if got := slices.Collect(intersectSpans(slices.Values(a), slices.Values(b))); !slices.Equal(got, tt.common) {
+					t.Errorf("intersectSpans(%v, %v) = %v, want %v", a, b, got, tt.common)
+				}
+			}
+		})
+	}
+}